@@ -0,0 +1,359 @@
+package scaffold
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// insertBatchRows caps the rows rendered into a single INSERT statement
+const insertBatchRows = 1000
+
+// BatchError reports one failed batch of a bulk insert
+type BatchError struct {
+	Offset int
+	Rows   int
+	Err    error
+}
+
+// Error satisfies the error interface
+func (e *BatchError) Error() string {
+	return "batch of " + strconv.Itoa(e.Rows) + " rows at offset " + strconv.Itoa(e.Offset) + " failed: " + e.Err.Error()
+}
+
+// Unwrap gives access to the underlying failure
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// BulkError collects every failed batch of a bulk insert
+type BulkError struct {
+	Batches []*BatchError
+}
+
+// Error satisfies the error interface
+func (e *BulkError) Error() string {
+	msgs := make([]string, 0, len(e.Batches))
+
+	for _, b := range e.Batches {
+		msgs = append(msgs, b.Error())
+	}
+
+	return strconv.Itoa(len(e.Batches)) + " insert batches failed: " + strings.Join(msgs, "; ")
+}
+
+// Is matches when any failed batch matches target
+func (e *BulkError) Is(target error) bool {
+	for _, b := range e.Batches {
+		if errors.Is(b, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// InsertMany inserts rows using multi-row VALUES statements, consecutive
+// rows setting the same cells share a batch sized to stay under the driver
+// parameter limit, a failed batch does not stop the others, the total
+// inserted is returned alongside a *BulkError describing any failures
+func (t *Table) InsertMany(rows []*Row) (int64, error) {
+	return t.InsertManyContext(context.Background(), rows)
+}
+
+// InsertManyContext works as InsertMany, the context cancels the query
+func (t *Table) InsertManyContext(ctx context.Context, rows []*Row) (int64, error) {
+	err := t.Validate()
+	if err != nil {
+		return 0, err
+	}
+
+	bulkErr := new(BulkError)
+
+	var total int64
+	var offset int
+	var fields []string
+
+	st := t.client().newStatement()
+	values := make([][]string, 0)
+
+	flush := func() {
+		if len(values) == 0 {
+			return
+		}
+
+		n, err := t.insertBatch(ctx, fields, values, st)
+		if err != nil {
+			bulkErr.Batches = append(bulkErr.Batches, &BatchError{Offset: offset, Rows: len(values), Err: err})
+		}
+		total += n
+
+		offset += len(values)
+		st = t.client().newStatement()
+		values = make([][]string, 0)
+	}
+
+	for i, row := range rows {
+		err := t.generateKeys(row)
+		if err != nil {
+			flush()
+			bulkErr.Batches = append(bulkErr.Batches, &BatchError{Offset: i, Rows: 1, Err: err})
+			offset = i + 1
+			continue
+		}
+
+		rowFields := t.setFields(row)
+
+		count, err := rowArgCount(st.dialect, row, rowFields)
+		if err != nil {
+			flush()
+			bulkErr.Batches = append(bulkErr.Batches, &BatchError{Offset: i, Rows: 1, Err: err})
+			offset = i + 1
+			continue
+		}
+
+		if count > st.maxArgs() {
+			flush()
+			bulkErr.Batches = append(bulkErr.Batches, &BatchError{Offset: i, Rows: 1, Err: errors.New("row exceeds the driver parameter limit")})
+			offset = i + 1
+			continue
+		}
+
+		if !sameFields(fields, rowFields) || len(fields) == 0 || len(st.Args())+count > st.maxArgs() || len(values) >= insertBatchRows {
+			flush()
+			fields = rowFields
+		}
+
+		placeholders, err := bindRow(st, row, fields)
+		if err != nil {
+			return total, err
+		}
+
+		values = append(values, placeholders)
+	}
+
+	flush()
+
+	if len(bulkErr.Batches) > 0 {
+		return total, bulkErr
+	}
+
+	return total, nil
+}
+
+// setFields lists the cells set on a row in table order
+func (t *Table) setFields(row *Row) []string {
+	fields := make([]string, 0)
+
+	for _, col := range t.Cells {
+		c, ok := row.Cells[col.Name]
+		if ok && !c.Exclude && c.IsSet() {
+			fields = append(fields, c.Name)
+		}
+	}
+
+	return fields
+}
+
+// sameFields compares two field lists
+func sameFields(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// insertBatch runs one multi-row insert
+func (t *Table) insertBatch(ctx context.Context, fields []string, values [][]string, st *statement) (int64, error) {
+	templateVars := make(map[string]interface{}, 0)
+	templateVars["table"] = t
+	templateVars["fields"] = fields
+	templateVars["values"] = values
+	templateVars["stmt"] = st
+
+	var b bytes.Buffer
+
+	err := render(&b, "insert", templateVars)
+	if err != nil {
+		return 0, t.queryError("execute template", "", err)
+	}
+
+	return t.client().execAffected(ctx, t.Name, b.String(), st)
+}
+
+// bindRow binds the given fields of a row
+func bindRow(st *statement, row *Row, fields []string) ([]string, error) {
+	placeholders := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		p, err := st.BindCell(row.Cells[field])
+		if err != nil {
+			return nil, err
+		}
+
+		placeholders = append(placeholders, p)
+	}
+
+	return placeholders, nil
+}
+
+// rowArgCount reports how many arguments a row binds
+func rowArgCount(d Dialect, row *Row, fields []string) (int, error) {
+	st := newStatement(d)
+
+	_, err := bindRow(st, row, fields)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(st.Args()), nil
+}
+
+// InsertManyCopy loads rows through the postgres COPY protocol in a single
+// transaction, it is much faster than InsertMany but all or nothing, every
+// row has to set the same cells as the first one
+func (t *Table) InsertManyCopy(rows []*Row) (int64, error) {
+	return t.InsertManyCopyContext(context.Background(), rows)
+}
+
+// InsertManyCopyContext works as InsertManyCopy, the context cancels the query
+func (t *Table) InsertManyCopyContext(ctx context.Context, rows []*Row) (int64, error) {
+	if t.client().dialect.Name() != "postgres" {
+		return 0, errors.New("COPY is only supported on postgres")
+	}
+
+	err := t.Validate()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	for i, row := range rows {
+		err = t.generateKeys(row)
+		if err != nil {
+			return 0, &BatchError{Offset: i, Rows: 1, Err: err}
+		}
+	}
+
+	fields := t.setFields(rows[0])
+
+	for i, row := range rows {
+		if !sameFields(fields, t.setFields(row)) {
+			return 0, &BatchError{Offset: i, Rows: 1, Err: errors.New("row sets different cells than the first row")}
+		}
+	}
+
+	// inside WithTx the copy joins the running transaction and leaves
+	// the outcome to it
+	db := t.session(ctx)
+	txn := db.tx
+	owned := txn == nil
+
+	if owned {
+		txn, err = db.conn.BeginTx(ctx, nil)
+		if err != nil {
+			return 0, t.queryError("begin transaction", "", err)
+		}
+	}
+
+	rollback := func() {
+		if owned {
+			txn.Rollback()
+		}
+	}
+
+	stmt, err := txn.PrepareContext(ctx, pq.CopyIn(t.Name, fields...))
+	if err != nil {
+		rollback()
+		return 0, t.queryError("prepare copy", "", err)
+	}
+
+	for i, row := range rows {
+		values := make([]interface{}, 0, len(fields))
+
+		for _, field := range fields {
+			v, err := copyValue(db, row.Cells[field])
+			if err != nil {
+				stmt.Close()
+				rollback()
+				return 0, &BatchError{Offset: i, Rows: 1, Err: err}
+			}
+
+			values = append(values, v)
+		}
+
+		_, err = stmt.ExecContext(ctx, values...)
+		if err != nil {
+			stmt.Close()
+			rollback()
+			return 0, &BatchError{Offset: i, Rows: 1, Err: t.queryError("copy rows", "", err)}
+		}
+	}
+
+	_, err = stmt.ExecContext(ctx)
+	if err != nil {
+		stmt.Close()
+		rollback()
+		return 0, &BatchError{Offset: 0, Rows: len(rows), Err: t.queryError("copy rows", "", err)}
+	}
+
+	err = stmt.Close()
+	if err != nil {
+		rollback()
+		return 0, t.queryError("close copy", "", err)
+	}
+
+	if owned {
+		err = txn.Commit()
+		if err != nil {
+			return 0, t.queryError("commit transaction", "", err)
+		}
+	}
+
+	return int64(len(rows)), nil
+}
+
+// copyValue converts a cell to the value COPY expects, unset and NULL
+// cells are copied as NULL
+func copyValue(db *DB, c *Cell) (interface{}, error) {
+	if c == nil || c.Data == nil {
+		return nil, nil
+	}
+
+	v, err := c.GetValue()
+	if err != nil {
+		return nil, nil
+	}
+
+	v, err = bindCellValue(c.Type, v)
+	if err != nil {
+		return nil, err
+	}
+
+	if isArrayCell(c.Type) {
+		return pq.Array(v), nil
+	}
+
+	zoned, ok := zonedTimeCell(c.Type)
+	t, isTime := v.(time.Time)
+	if ok && isTime {
+		return db.dialect.BindTime(t, zoned, db.TimeLocation()), nil
+	}
+
+	return v, nil
+}
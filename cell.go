@@ -1,8 +1,14 @@
 package scaffold
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
 )
 
 // CellType for vague match against SQL types
@@ -25,6 +31,12 @@ const (
 	CellBytes
 	CellBytesArray
 	CellJSON
+	CellDecimal
+	CellDecimalArray
+	CellUUID
+	CellUUIDArray
+	CellDatetimeTZ
+	CellDatetimeTZArray
 )
 
 // SQLCell explains that base properties of a cell
@@ -33,13 +45,29 @@ type SQLCell interface {
 	Raw() (interface{}, error)
 }
 
-// Cell type container
+// Cell type container, Precision and Scale size decimal columns, Generate
+// fills an unset UUID primary key on insert
 type Cell struct {
-	Name    string
-	SQL     string
-	Type    CellType
-	Exclude bool
-	Data    SQLCell
+	Name       string
+	SQL        string
+	Type       CellType
+	Exclude    bool
+	PrimaryKey bool
+	Precision  int
+	Scale      int
+	Generate   UUIDGenerator
+	Data       SQLCell
+}
+
+// asString reads driver text values that arrive as bytes or strings
+func asString(data interface{}) string {
+	switch v := data.(type) {
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	}
+	return ""
 }
 
 // CellTarget for a cell
@@ -49,9 +77,44 @@ func (c *Cell) CellTarget() interface{} {
 
 // GetValue from cell
 func (c *Cell) GetValue() (interface{}, error) {
+	if c.Data == nil {
+		return nil, errors.New("Unset value")
+	}
+
 	return c.Data.Raw()
 }
 
+// IsSet reports whether the cell holds a value or an explicit NULL, unset
+// cells are left out of inserts and updates so database defaults apply
+func (c *Cell) IsSet() bool {
+	return c.Data != nil
+}
+
+// IsNull reports whether the cell holds an explicit NULL
+func (c *Cell) IsNull() bool {
+	if c.Data == nil {
+		return false
+	}
+
+	_, isNull := c.Data.(*nullCell)
+	if isNull {
+		return true
+	}
+
+	_, err := c.Data.Raw()
+	return err != nil
+}
+
+// SetNull stores an explicit NULL in the cell
+func (c *Cell) SetNull() {
+	c.Data = new(nullCell)
+}
+
+// Unset clears the cell so it is left out of inserts and updates
+func (c *Cell) Unset() {
+	c.Data = nil
+}
+
 // Bytes from cell
 func (c *Cell) Bytes() ([]byte, error) {
 	v, err := c.GetValue()
@@ -61,7 +124,7 @@ func (c *Cell) Bytes() ([]byte, error) {
 
 	vv, ok := v.([]byte)
 	if !ok {
-		return []byte(""), err
+		return []byte(""), typeMismatch(c, "[]byte")
 	}
 
 	return vv, nil
@@ -70,7 +133,7 @@ func (c *Cell) Bytes() ([]byte, error) {
 // SetBytes to cell
 func (c *Cell) SetBytes(x []byte) error {
 	if c.Type != CellBytes {
-		return errors.New("set incorrect type")
+		return typeMismatch(c, "a bytes cell")
 	}
 
 	d := NewSQLBytes()
@@ -81,6 +144,21 @@ func (c *Cell) SetBytes(x []byte) error {
 	return nil
 }
 
+// BytesArray from cell
+func (c *Cell) BytesArray() ([][]byte, error) {
+	v, err := c.GetValue()
+	if err != nil {
+		return [][]byte{}, err
+	}
+
+	vv, ok := v.([][]byte)
+	if !ok {
+		return [][]byte{}, typeMismatch(c, "[][]byte")
+	}
+
+	return vv, nil
+}
+
 // Bool from cell
 func (c *Cell) Bool() (bool, error) {
 	v, err := c.GetValue()
@@ -90,7 +168,7 @@ func (c *Cell) Bool() (bool, error) {
 
 	vv, ok := v.(bool)
 	if !ok {
-		return false, err
+		return false, typeMismatch(c, "bool")
 	}
 
 	return vv, nil
@@ -99,7 +177,7 @@ func (c *Cell) Bool() (bool, error) {
 // SetBool to cell
 func (c *Cell) SetBool(x bool) error {
 	if c.Type != CellBool {
-		return errors.New("set incorrect type")
+		return typeMismatch(c, "a bool cell")
 	}
 
 	d := NewSQLBool()
@@ -119,7 +197,7 @@ func (c *Cell) BoolArray() ([]bool, error) {
 
 	vv, ok := v.([]bool)
 	if !ok {
-		return []bool{}, err
+		return []bool{}, typeMismatch(c, "[]bool")
 	}
 
 	return vv, nil
@@ -134,7 +212,7 @@ func (c *Cell) String() (string, error) {
 
 	vv, ok := v.(string)
 	if !ok {
-		return "", err
+		return "", typeMismatch(c, "string")
 	}
 
 	return vv, nil
@@ -143,7 +221,7 @@ func (c *Cell) String() (string, error) {
 // SetString to cell
 func (c *Cell) SetString(x string) error {
 	if c.Type != CellString {
-		return errors.New("set incorrect type")
+		return typeMismatch(c, "a string cell")
 	}
 
 	d := NewSQLString()
@@ -163,7 +241,7 @@ func (c *Cell) StringArray() ([]string, error) {
 
 	vv, ok := v.([]string)
 	if !ok {
-		return []string{}, err
+		return []string{}, typeMismatch(c, "[]string")
 	}
 
 	return vv, nil
@@ -178,7 +256,7 @@ func (c *Cell) Int() (int64, error) {
 
 	vv, ok := v.(int64)
 	if !ok {
-		return 0, err
+		return 0, typeMismatch(c, "int64")
 	}
 
 	return vv, nil
@@ -187,7 +265,7 @@ func (c *Cell) Int() (int64, error) {
 // SetInt to cell
 func (c *Cell) SetInt(x int64) error {
 	if c.Type != CellInt {
-		return errors.New("set incorrect type")
+		return typeMismatch(c, "an int cell")
 	}
 
 	d := NewSQLInt()
@@ -207,7 +285,7 @@ func (c *Cell) IntArray() ([]int64, error) {
 
 	vv, ok := v.([]int64)
 	if !ok {
-		return []int64{}, err
+		return []int64{}, typeMismatch(c, "[]int64")
 	}
 
 	return vv, nil
@@ -222,7 +300,7 @@ func (c *Cell) Float() (float64, error) {
 
 	vv, ok := v.(float64)
 	if !ok {
-		return 0, err
+		return 0, typeMismatch(c, "float64")
 	}
 
 	return vv, nil
@@ -231,7 +309,7 @@ func (c *Cell) Float() (float64, error) {
 // SetFloat to cell
 func (c *Cell) SetFloat(x float64) error {
 	if c.Type != CellFloat {
-		return errors.New("set incorrect type")
+		return typeMismatch(c, "a float cell")
 	}
 
 	d := NewSQLFloat()
@@ -251,7 +329,7 @@ func (c *Cell) FloatArray() ([]float64, error) {
 
 	vv, ok := v.([]float64)
 	if !ok {
-		return []float64{}, err
+		return []float64{}, typeMismatch(c, "[]float64")
 	}
 
 	return vv, nil
@@ -266,7 +344,7 @@ func (c *Cell) Date() (time.Time, error) {
 
 	vv, ok := v.(time.Time)
 	if !ok {
-		return time.Time{}, err
+		return time.Time{}, typeMismatch(c, "time.Time")
 	}
 
 	return vv, nil
@@ -275,7 +353,7 @@ func (c *Cell) Date() (time.Time, error) {
 // SetDate to cell
 func (c *Cell) SetDate(x time.Time) error {
 	if c.Type != CellDate {
-		return errors.New("set incorrect type")
+		return typeMismatch(c, "a date cell")
 	}
 
 	d := NewSQLDate()
@@ -295,7 +373,7 @@ func (c *Cell) DateArray() ([]time.Time, error) {
 
 	vv, ok := v.([]time.Time)
 	if !ok {
-		return []time.Time{}, err
+		return []time.Time{}, typeMismatch(c, "[]time.Time")
 	}
 
 	return vv, nil
@@ -310,23 +388,23 @@ func (c *Cell) Datetime() (time.Time, error) {
 
 	vv, ok := v.(time.Time)
 	if !ok {
-		return time.Time{}, err
+		return time.Time{}, typeMismatch(c, "time.Time")
 	}
 
 	return vv, nil
 }
 
-// SetDatetime to cell
+// SetDatetime to cell, for cells with and without a time zone
 func (c *Cell) SetDatetime(x time.Time) error {
-	if c.Type != CellDate {
-		return errors.New("set incorrect type")
+	switch c.Type {
+	case CellDatetime:
+		c.Data = &SQLDatetime{Valid: true, Value: x}
+	case CellDatetimeTZ:
+		c.Data = &SQLDatetimeTZ{Valid: true, Value: x}
+	default:
+		return typeMismatch(c, "a datetime cell")
 	}
 
-	d := NewSQLDatetime()
-	d.Scan(x)
-
-	c.Data = d
-
 	return nil
 }
 
@@ -339,8 +417,250 @@ func (c *Cell) DatetimeArray() ([]time.Time, error) {
 
 	vv, ok := v.([]time.Time)
 	if !ok {
-		return []time.Time{}, err
+		return []time.Time{}, typeMismatch(c, "[]time.Time")
+	}
+
+	return vv, nil
+}
+
+// Decimal from cell
+func (c *Cell) Decimal() (decimal.Decimal, error) {
+	v, err := c.GetValue()
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	vv, ok := v.(decimal.Decimal)
+	if !ok {
+		return decimal.Zero, typeMismatch(c, "decimal.Decimal")
 	}
 
 	return vv, nil
 }
+
+// SetDecimal to cell
+func (c *Cell) SetDecimal(x decimal.Decimal) error {
+	if c.Type != CellDecimal {
+		return typeMismatch(c, "a decimal cell")
+	}
+
+	d := NewSQLDecimal()
+	d.Valid = true
+	d.Value = x
+
+	c.Data = d
+
+	return nil
+}
+
+// DecimalArray from cell
+func (c *Cell) DecimalArray() ([]decimal.Decimal, error) {
+	v, err := c.GetValue()
+	if err != nil {
+		return []decimal.Decimal{}, err
+	}
+
+	vv, ok := v.([]decimal.Decimal)
+	if !ok {
+		return []decimal.Decimal{}, typeMismatch(c, "[]decimal.Decimal")
+	}
+
+	return vv, nil
+}
+
+// UUID from cell
+func (c *Cell) UUID() (UUID, error) {
+	v, err := c.GetValue()
+	if err != nil {
+		return UUID{}, err
+	}
+
+	vv, ok := v.(UUID)
+	if !ok {
+		return UUID{}, typeMismatch(c, "UUID")
+	}
+
+	return vv, nil
+}
+
+// SetUUID to cell
+func (c *Cell) SetUUID(x UUID) error {
+	if c.Type != CellUUID {
+		return typeMismatch(c, "a uuid cell")
+	}
+
+	d := NewSQLUUID()
+	d.Valid = true
+	d.Value = x
+
+	c.Data = d
+
+	return nil
+}
+
+// UUIDArray from cell
+func (c *Cell) UUIDArray() ([]UUID, error) {
+	v, err := c.GetValue()
+	if err != nil {
+		return []UUID{}, err
+	}
+
+	vv, ok := v.([]UUID)
+	if !ok {
+		return []UUID{}, typeMismatch(c, "[]UUID")
+	}
+
+	return vv, nil
+}
+
+// JSON from cell
+func (c *Cell) JSON() ([]byte, error) {
+	if c.Type != CellJSON {
+		return []byte(""), typeMismatch(c, "a json cell")
+	}
+
+	v, err := c.GetValue()
+	if err != nil {
+		return []byte(""), err
+	}
+
+	vv, ok := v.([]byte)
+	if !ok {
+		return []byte(""), typeMismatch(c, "[]byte")
+	}
+
+	return vv, nil
+}
+
+// SetJSON to cell, the document must be valid JSON
+func (c *Cell) SetJSON(x []byte) error {
+	if c.Type != CellJSON {
+		return typeMismatch(c, "a json cell")
+	}
+
+	if !json.Valid(x) {
+		return errors.New("invalid JSON for cell " + c.Name)
+	}
+
+	d := NewSQLJSON()
+	d.Scan(x)
+
+	c.Data = d
+
+	return nil
+}
+
+// JSONPath reads the value at a path of a JSON cell, such as
+// "address.city", the result does not exist when the path is missing
+func (c *Cell) JSONPath(path string) (gjson.Result, error) {
+	v, err := c.JSON()
+	if err != nil {
+		return gjson.Result{}, err
+	}
+
+	return gjson.GetBytes(v, path), nil
+}
+
+// SetJSONPath sets the value at a path of a JSON cell, a cell without a
+// value starts from an empty object
+func (c *Cell) SetJSONPath(path string, value interface{}) error {
+	v, err := c.JSON()
+	if errors.Is(err, ErrTypeMismatch) {
+		return err
+	}
+	if err != nil {
+		v = []byte("{}")
+	}
+
+	v, err = sjson.SetBytes(v, path, value)
+	if err != nil {
+		return err
+	}
+
+	return c.SetJSON(v)
+}
+
+// nullCell is an explicit NULL set on a cell
+type nullCell struct{}
+
+// Raw Null->Raw
+func (x *nullCell) Raw() (interface{}, error) {
+	return nil, errors.New("Invalid value")
+}
+
+// Target gets the scannable target for nullCell
+func (x *nullCell) Target() interface{} {
+	return new(interface{})
+}
+
+// textValue holds a column of unknown type as text
+type textValue struct {
+	Valid bool
+	Value string
+}
+
+// Raw text->Raw
+func (x *textValue) Raw() (interface{}, error) {
+	if !x.Valid {
+		return x.Value, errors.New("Invalid value")
+	}
+
+	return x.Value, nil
+}
+
+// Target gets the scannable target for textValue
+func (x *textValue) Target() interface{} {
+	return x
+}
+
+// Scan interface->text
+func (x *textValue) Scan(data interface{}) error {
+	switch v := data.(type) {
+	case nil:
+		x.Value = ""
+	case []byte:
+		x.Value = string(v)
+	case string:
+		x.Value = v
+	case time.Time:
+		x.Value = v.Format(time.RFC3339Nano)
+	default:
+		x.Value = fmt.Sprint(v)
+	}
+	x.Valid = data != nil
+
+	return nil
+}
+
+// anyValue holds whatever the driver returns for a column of unknown type
+type anyValue struct {
+	Valid bool
+	Value interface{}
+}
+
+// Raw any->Raw
+func (x *anyValue) Raw() (interface{}, error) {
+	if !x.Valid {
+		return x.Value, errors.New("Invalid value")
+	}
+
+	return x.Value, nil
+}
+
+// Target gets the scannable target for anyValue
+func (x *anyValue) Target() interface{} {
+	return x
+}
+
+// Scan interface->any
+func (x *anyValue) Scan(data interface{}) error {
+	switch v := data.(type) {
+	case []byte:
+		x.Value = append([]byte{}, v...)
+	default:
+		x.Value = v
+	}
+	x.Valid = data != nil
+
+	return nil
+}
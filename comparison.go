@@ -0,0 +1,305 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// Comparison applied between a filter field and its value
+type Comparison string
+
+// Comparison const
+const (
+	Eq         Comparison = "="
+	Ne         Comparison = "<>"
+	Lt         Comparison = "<"
+	Lte        Comparison = "<="
+	Gt         Comparison = ">"
+	Gte        Comparison = ">="
+	In         Comparison = "IN"
+	NotIn      Comparison = "NOT IN"
+	Between    Comparison = "BETWEEN"
+	IsNull     Comparison = "IS NULL"
+	NotNull    Comparison = "IS NOT NULL"
+	Like       Comparison = "LIKE"
+	ILike      Comparison = "ILIKE"
+	StartsWith Comparison = "STARTS WITH"
+	Contains   Comparison = "CONTAINS"
+	Has        Comparison = "HAS"
+	HasAll     Comparison = "HAS ALL"
+	HasAny     Comparison = "HAS ANY"
+	// JSONContains matches JSON fields containing the value as a document,
+	// the value is JSON text or marshalled to JSON
+	JSONContains Comparison = "@>"
+)
+
+// comparisonAliases maps accepted spellings onto the typed set
+var comparisonAliases = map[string]Comparison{
+	"=":             Eq,
+	"==":            Eq,
+	"<>":            Ne,
+	"!=":            Ne,
+	"<":             Lt,
+	"<=":            Lte,
+	">":             Gt,
+	">=":            Gte,
+	"IN":            In,
+	"NOT IN":        NotIn,
+	"BETWEEN":       Between,
+	"IS NULL":       IsNull,
+	"IS NOT NULL":   NotNull,
+	"NOT NULL":      NotNull,
+	"LIKE":          Like,
+	"ILIKE":         ILike,
+	"STARTS WITH":   StartsWith,
+	"CONTAINS":      Contains,
+	"HAS":           Has,
+	"HAS ALL":       HasAll,
+	"HAS ANY":       HasAny,
+	"@>":            JSONContains,
+	"JSON CONTAINS": JSONContains,
+}
+
+// Normalize returns the canonical comparison, ok is false when unsupported
+func (c Comparison) Normalize() (Comparison, bool) {
+	v, ok := comparisonAliases[normalizeKeyword(string(c))]
+	return v, ok
+}
+
+// operands reports how many values a comparison needs, -1 for one or more
+func (c Comparison) operands() int {
+	switch c {
+	case IsNull, NotNull:
+		return 0
+	case In, NotIn, HasAll, HasAny:
+		return -1
+	case Between:
+		return 2
+	}
+	return 1
+}
+
+// ranged reports whether a comparison depends on the order of values
+func (c Comparison) ranged() bool {
+	switch c {
+	case Lt, Lte, Gt, Gte, Between:
+		return true
+	}
+	return false
+}
+
+// listValues unpacks a slice value, ok is false for scalars and []byte
+func listValues(v interface{}) ([]interface{}, bool) {
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+
+	list := make([]interface{}, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		list = append(list, rv.Index(i).Interface())
+	}
+
+	return list, true
+}
+
+// checkOperands verifies a filter value has the shape its comparison needs
+// and holds values that can be bound
+func checkOperands(c Comparison, v interface{}) error {
+	list, isList := listValues(v)
+
+	switch c.operands() {
+	case 0:
+		if v != nil {
+			return errors.New("takes no value")
+		}
+	case -1:
+		if !isList || len(list) == 0 {
+			return errors.New("needs a non-empty list of values")
+		}
+	case 2:
+		if !isList || len(list) != 2 {
+			return errors.New("needs a list of exactly two values")
+		}
+	default:
+		if c == JSONContains {
+			// documents may be lists or objects
+			if v == nil {
+				return errors.New("needs a JSON document")
+			}
+			break
+		}
+		if isList || v == nil {
+			return errors.New("needs a single value")
+		}
+		if c == StartsWith || c == Contains {
+			_, ok := v.(string)
+			if !ok {
+				return errors.New("needs a string value")
+			}
+		}
+	}
+
+	if c.operands() == 0 || c == JSONContains {
+		return nil
+	}
+
+	if !isList {
+		list = []interface{}{v}
+	}
+
+	for _, item := range list {
+		_, err := bindValue(item)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// likeEscape is the escape character for LIKE patterns, a backslash would
+// need escaping itself in MySQL string literals
+const likeEscape = "!"
+
+// escapeLike escapes the LIKE wildcards in a literal pattern
+func escapeLike(s string) string {
+	r := strings.NewReplacer(likeEscape, likeEscape+likeEscape, `%`, likeEscape+`%`, `_`, likeEscape+`_`)
+	return r.Replace(s)
+}
+
+// Compare renders a filter comparison with its values bound
+func (s *statement) Compare(f Filter) (string, error) {
+	c, ok := f.Comparison.Normalize()
+	if !ok {
+		return "", errors.New("unsupported comparison " + string(f.Comparison))
+	}
+
+	err := checkOperands(c, f.Value)
+	if err != nil {
+		return "", errors.New(string(c) + " " + err.Error())
+	}
+
+	if f.Path == "" && c.ranged() && s.unordered(f.Field) {
+		return "", errors.New(string(c) + " on " + f.Field + ", its values do not sort in " + s.dialect.Name())
+	}
+
+	field := s.Quote(f.Field)
+	zoned := s.zoned(f)
+
+	if f.Path != "" {
+		field = s.dialect.JSONPath(field, splitPath(f.Path), s.bindRaw)
+	}
+
+	switch c {
+	case IsNull, NotNull:
+		return field + " " + string(c), nil
+	case Between:
+		list, _ := listValues(f.Value)
+
+		lo, err := s.bindOne(list[0], zoned)
+		if err != nil {
+			return "", err
+		}
+
+		hi, err := s.bindOne(list[1], zoned)
+		if err != nil {
+			return "", err
+		}
+
+		return field + " BETWEEN " + lo + " AND " + hi, nil
+	case ILike:
+		p, err := s.bindOne(f.Value, zoned)
+		if err != nil {
+			return "", err
+		}
+
+		return s.dialect.ILike(field, p), nil
+	case Has:
+		p, err := s.bindOne(f.Value, zoned)
+		if err != nil {
+			return "", err
+		}
+
+		return s.dialect.ArrayHas(field, p), nil
+	case HasAll, HasAny:
+		list, _ := listValues(f.Value)
+		parts := make([]string, 0, len(list))
+
+		for _, item := range list {
+			p, err := s.bindOne(item, zoned)
+			if err != nil {
+				return "", err
+			}
+
+			parts = append(parts, s.dialect.ArrayHas(field, p))
+		}
+
+		join := " AND "
+		if c == HasAny {
+			join = " OR "
+		}
+
+		return "(" + strings.Join(parts, join) + ")", nil
+	case JSONContains:
+		doc, err := jsonDocument(f.Value)
+		if err != nil {
+			return "", err
+		}
+
+		return s.dialect.JSONContains(field, s.bindRaw(doc))
+	case StartsWith, Contains:
+		pattern := escapeLike(f.Value.(string)) + "%"
+		if c == Contains {
+			pattern = "%" + pattern
+		}
+
+		p, err := s.bindOne(pattern, zoned)
+		if err != nil {
+			return "", err
+		}
+
+		return field + " LIKE " + p + " ESCAPE '" + likeEscape + "'", nil
+	}
+
+	p, err := s.bindField(f.Value, zoned)
+	if err != nil {
+		return "", err
+	}
+
+	return field + " " + string(c) + " " + p, nil
+}
+
+// splitPath splits a dotted JSON path into its keys
+func splitPath(path string) []string {
+	return strings.Split(path, ".")
+}
+
+// jsonDocument is the JSON text of a containment value, strings and bytes
+// are taken as JSON already
+func jsonDocument(v interface{}) (string, error) {
+	var b []byte
+
+	switch x := v.(type) {
+	case string:
+		b = []byte(x)
+	case []byte:
+		b = x
+	default:
+		var err error
+
+		b, err = json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if !json.Valid(b) {
+		return "", errors.New("@> needs a JSON document")
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,396 @@
+package scaffold
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DB binds table definitions to a database connection and the dialect
+// used to talk to it
+type DB struct {
+	conn  *sql.DB
+	exec  executor
+	tx    *sql.Tx
+	depth int
+	// origin is the DB a transaction was opened from, nil outside one
+	origin  *DB
+	dialect Dialect
+	tables  map[string]*Table
+	// strictTypes makes GetRaw fail on column types without a cell type
+	strictTypes bool
+	// json is handed to the rows the DB makes
+	json JSONOptions
+	// location datetimes are read and written in, UTC when nil
+	location *time.Location
+	mu       sync.RWMutex
+}
+
+// executor is the part of *sql.DB and *sql.Tx queries are run through
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// New makes a DB for a connection, postgres is used when d is nil
+func New(conn *sql.DB, d Dialect) *DB {
+	s := new(DB)
+
+	if d == nil {
+		d = new(PostgresDialect)
+	}
+
+	s.conn = conn
+	if conn != nil {
+		s.exec = conn
+	}
+	s.dialect = d
+	s.tables = make(map[string]*Table)
+
+	return s
+}
+
+// Conn returns the underlying connection
+func (s *DB) Conn() *sql.DB {
+	return s.conn
+}
+
+// Dialect returns the dialect used to render SQL
+func (s *DB) Dialect() Dialect {
+	return s.dialect
+}
+
+// SetStrictTypes makes GetRaw fail with ErrUnknownType on columns whose
+// database type has no cell type, by default they are read as text
+func (s *DB) SetStrictTypes(strict bool) {
+	s.strictTypes = strict
+}
+
+// SetTimeLocation sets the location datetimes are read and written in,
+// datetimes without a time zone hold the wall clock of this location and
+// zoned datetimes are converted to it, UTC when nil or never set
+func (s *DB) SetTimeLocation(loc *time.Location) {
+	s.location = loc
+}
+
+// TimeLocation returns the location set with SetTimeLocation
+func (s *DB) TimeLocation() *time.Location {
+	return orUTC(s.location)
+}
+
+// SetDecimalJSONNumbers makes Row.AsJSON write decimals as JSON numbers
+// rather than strings for rows read through the DB, the digits are
+// written as they are either way
+func (s *DB) SetDecimalJSONNumbers(on bool) {
+	s.json.DecimalNumbers = on
+}
+
+// NewTable generates a table bound to the DB
+func (s *DB) NewTable(name string, cells []*Cell) *Table {
+	t := new(Table)
+
+	t.Name = name
+	t.Cells = cells
+
+	return s.Register(t)
+}
+
+// Register binds a copy of a table definition to the DB, so the same
+// definition can be used against several databases
+func (s *DB) Register(t *Table) *Table {
+	bound := new(Table)
+
+	bound.Name = t.Name
+	bound.Cells = t.Cells
+	bound.db = s
+
+	s.mu.Lock()
+	s.tables[bound.Name] = bound
+	s.mu.Unlock()
+
+	return bound
+}
+
+// GetTable gets table def
+func (s *DB) GetTable(name string) *Table {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	val, found := s.tables[name]
+	if found {
+		return val
+	}
+
+	return nil
+}
+
+// True returns the true literal for the dialect
+func (s *DB) True() string {
+	return s.dialect.True()
+}
+
+// False returns the false literal for the dialect
+func (s *DB) False() string {
+	return s.dialect.False()
+}
+
+// Result reports the outcome of Exec, LastInsertID is 0 when the driver
+// does not report one
+type Result struct {
+	RowsAffected int64
+	LastInsertID int64
+}
+
+// Raw runs a raw query
+func (s *DB) Raw(q string) error {
+	return s.RawContext(context.Background(), q)
+}
+
+// RawContext works as Raw, the context cancels the query
+func (s *DB) RawContext(ctx context.Context, q string) error {
+	_, err := s.ExecContext(ctx, q)
+	return err
+}
+
+// Exec runs a statement that returns no rows, args are bound to the
+// placeholders of the dialect or to :name when a single Params is given
+func (s *DB) Exec(q string, args ...interface{}) (Result, error) {
+	return s.ExecContext(context.Background(), q, args...)
+}
+
+// ExecContext works as Exec, the context cancels the query
+func (s *DB) ExecContext(ctx context.Context, q string, args ...interface{}) (Result, error) {
+	var result Result
+
+	st := s.newStatement()
+
+	q, err := bindArgs(st, q, args)
+	if err != nil {
+		return result, err
+	}
+
+	res, err := s.bound(ctx).exec.ExecContext(ctx, q, st.Args()...)
+	if err != nil {
+		return result, s.queryError("execute query", "", q, err)
+	}
+
+	result.RowsAffected, err = res.RowsAffected()
+	if err != nil {
+		return result, s.queryError("read rows affected", "", q, err)
+	}
+
+	// drivers without last insert ids, such as pq, report an error here
+	id, err := res.LastInsertId()
+	if err == nil {
+		result.LastInsertID = id
+	}
+
+	return result, nil
+}
+
+// GetRaw runs a raw query that expects results
+func (s *DB) GetRaw(q string) (*Rows, error) {
+	return s.QueryContext(context.Background(), q)
+}
+
+// GetRawContext works as GetRaw, the context cancels the query
+func (s *DB) GetRawContext(ctx context.Context, q string) (*Rows, error) {
+	return s.QueryContext(ctx, q)
+}
+
+// Query runs a query and reads every row, columns are typed by the
+// dialect from their database type, args bind as they do for Exec
+func (s *DB) Query(q string, args ...interface{}) (*Rows, error) {
+	return s.QueryContext(context.Background(), q, args...)
+}
+
+// QueryContext works as Query, the context cancels the query
+func (s *DB) QueryContext(ctx context.Context, q string, args ...interface{}) (*Rows, error) {
+	result := new(Rows)
+
+	result.Rows = make([]*Row, 0)
+	result.Cols = make([]string, 0)
+
+	st := s.newStatement()
+
+	q, err := bindArgs(st, q, args)
+	if err != nil {
+		return result, err
+	}
+
+	rows, err := s.bound(ctx).exec.QueryContext(ctx, q, st.Args()...)
+	if err != nil {
+		return result, s.queryError("execute query", "", q, err)
+	}
+	defer rows.Close()
+
+	cols, err := rows.ColumnTypes()
+	if err != nil {
+		return result, s.queryError("extract column types", "", q, err)
+	}
+
+	for _, v := range cols {
+		result.Cols = append(result.Cols, v.Name())
+	}
+
+	types := make([]CellType, len(cols))
+	known := make([]bool, len(cols))
+
+	for i, c := range cols {
+		types[i], known[i] = s.dialect.CellType(c.DatabaseTypeName())
+		if known[i] && newCellData(types[i]) == nil {
+			known[i] = false
+		}
+
+		if !known[i] {
+			if s.strictTypes {
+				err := fmt.Errorf("%w %s for column %s", ErrUnknownType, c.DatabaseTypeName(), c.Name())
+				return result, s.queryError("scan row", "", q, err)
+			}
+
+			// unknown columns are read as text rather than failing the query
+			types[i] = CellString
+		}
+	}
+
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		row := new(Row)
+		row.Cells = make(map[string]*Cell, 0)
+		row.json = s.json
+
+		scanList := make([]interface{}, 0)
+
+		for i, c := range cols {
+			cell := new(Cell)
+			row.Cells[c.Name()] = cell
+
+			cell.Name = c.Name()
+			cell.Type = types[i]
+
+			if known[i] {
+				cell.Data = s.cellData(types[i])
+				scanList = append(scanList, scanTarget(s.dialect, cell))
+			} else {
+				text := new(textValue)
+				cell.Data = text
+				scanList = append(scanList, text)
+			}
+		}
+
+		err := rows.Scan(scanList...)
+		if err != nil {
+			return result, s.queryError("scan row", "", q, err)
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return result, s.queryError("read rows", "", q, err)
+	}
+
+	return result, nil
+}
+
+// CreateTable creates the table if it does not exist yet
+func (s *DB) CreateTable(t *Table) error {
+	return s.CreateTableContext(context.Background(), t)
+}
+
+// CreateTableContext works as CreateTable, the context cancels the query
+func (s *DB) CreateTableContext(ctx context.Context, t *Table) error {
+	q, err := s.renderCreateTable(t)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.bound(ctx).exec.ExecContext(ctx, q)
+	if err != nil {
+		return s.queryError("create table", t.Name, q, err)
+	}
+
+	return nil
+}
+
+// renderCreateTable builds the CREATE TABLE statement for a table
+func (s *DB) renderCreateTable(t *Table) (string, error) {
+	var b bytes.Buffer
+
+	err := t.Validate()
+	if err != nil {
+		return "", err
+	}
+
+	templateVars := make(map[string]interface{}, 0)
+	templateVars["table"] = t
+	templateVars["keys"] = t.primaryKeys()
+	templateVars["stmt"] = s.newStatement()
+
+	err = render(&b, "schema", templateVars)
+	if err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// Bootstrap creates every table, stopping at the first failure
+func (s *DB) Bootstrap(tables []*Table) error {
+	return s.BootstrapContext(context.Background(), tables)
+}
+
+// BootstrapContext works as Bootstrap, the context cancels the query
+func (s *DB) BootstrapContext(ctx context.Context, tables []*Table) error {
+	for _, table := range tables {
+		err := s.CreateTableContext(ctx, table)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newStatement makes a statement for the dialect and time location
+func (s *DB) newStatement() *statement {
+	st := newStatement(s.dialect)
+	st.loc = s.TimeLocation()
+
+	return st
+}
+
+// cellData makes an empty value holder for a cell type read through the
+// DB, datetimes are read in its time location
+func (s *DB) cellData(t CellType) SQLCell {
+	data := newCellData(t)
+
+	l, ok := data.(locator)
+	if ok {
+		l.setLocation(s.TimeLocation())
+	}
+
+	return data
+}
+
+// execAffected runs a rendered statement and reports the rows affected
+func (s *DB) execAffected(ctx context.Context, table string, q string, st *statement) (int64, error) {
+	res, err := s.bound(ctx).exec.ExecContext(ctx, q, st.Args()...)
+	if err != nil {
+		return 0, s.queryError("execute query", table, q, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, s.queryError("read rows affected", table, q, err)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,136 @@
+package scaffold
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dialect describes how SQL is written for a particular database
+type Dialect interface {
+	// Name of the dialect, as passed to Bootstrap
+	Name() string
+	// Placeholder for the nth bound argument, counting from 1
+	Placeholder(n int) string
+	// Quote an identifier
+	Quote(name string) string
+	// True and False literals
+	True() string
+	False() string
+	// MaxArgs is the most arguments a single statement may bind
+	MaxArgs() int
+	// SupportsReturning reports whether INSERT, UPDATE and DELETE take a
+	// RETURNING clause
+	SupportsReturning() bool
+	// Array renders the value of an array cell, bind adds one argument and
+	// returns its placeholder
+	Array(t CellType, values []interface{}, bind func(interface{}) string) (string, error)
+	// NullArray renders a NULL array cell
+	NullArray(t CellType) string
+	// NativeArrays reports whether array cells use database arrays, when
+	// false they are stored as JSON text
+	NativeArrays() bool
+	// Upsert renders the clause following an INSERT that turns it into an
+	// upsert, with no updates a conflicting row is left alone
+	Upsert(conflict []string, updates []string, fields []string) (string, error)
+	// DefaultValues renders the values of an insert that sets no columns
+	DefaultValues() string
+	// ArrayHas renders a test for an array cell holding a value
+	ArrayHas(field string, placeholder string) string
+	// ILike renders a case insensitive LIKE
+	ILike(field string, placeholder string) string
+	// LimitOffset renders the limit and offset of a query, negative values
+	// mean no limit or no offset
+	LimitOffset(limit int, offset int) string
+	// ColumnType is the DDL type used for cells that do not set SQL
+	ColumnType(t CellType) string
+	// CellType maps a driver database type name onto a cell type
+	CellType(dbType string) (CellType, bool)
+	// JSONPath renders the value at a path inside a JSON field, keys are
+	// bound with bind rather than written into the SQL
+	JSONPath(field string, path []string, bind func(interface{}) string) string
+	// JSONContains renders a test for a JSON field containing a document
+	JSONContains(field string, placeholder string) (string, error)
+	// BindTime converts a datetime before it is bound, zoned is false for
+	// columns without a time zone, which store the wall clock of loc, the
+	// time location of the DB
+	BindTime(v time.Time, zoned bool, loc *time.Location) interface{}
+	// ClassifyError maps a driver error onto one of the Err values, with
+	// the constraint and columns involved when the driver reports them
+	ClassifyError(err error) (kind error, constraint string, columns []string)
+}
+
+// DialectFor returns the dialect for a mode name, postgres when unknown
+func DialectFor(mode string) Dialect {
+	switch strings.ToLower(mode) {
+	case "sqlite", "sqlite3":
+		return new(SQLiteDialect)
+	case "mysql", "mariadb":
+		return new(MySQLDialect)
+	}
+	return new(PostgresDialect)
+}
+
+// SetDialect changes the dialect used by the package level functions
+func SetDialect(d Dialect) {
+	std.dialect = d
+}
+
+// GetDialect returns the dialect used by the package level functions
+func GetDialect() Dialect {
+	return std.dialect
+}
+
+// quoteIdent doubles embedded quote characters and wraps the name in them
+func quoteIdent(name string, quote string) string {
+	return quote + strings.ReplaceAll(name, quote, quote+quote) + quote
+}
+
+// onConflict renders the ON CONFLICT clause shared by postgres and sqlite
+func onConflict(d Dialect, conflict []string, updates []string) (string, error) {
+	if len(updates) > 0 && len(conflict) == 0 {
+		return "", &ValidationError{Reason: "upsert updates need conflict columns"}
+	}
+
+	s := "ON CONFLICT"
+
+	if len(conflict) > 0 {
+		quoted := make([]string, 0, len(conflict))
+		for _, c := range conflict {
+			quoted = append(quoted, d.Quote(c))
+		}
+
+		s += " (" + strings.Join(quoted, ", ") + ")"
+	}
+
+	if len(updates) == 0 {
+		return s + " DO NOTHING", nil
+	}
+
+	sets := make([]string, 0, len(updates))
+	for _, u := range updates {
+		sets = append(sets, d.Quote(u)+" = EXCLUDED."+d.Quote(u))
+	}
+
+	return s + " DO UPDATE SET\n\t" + strings.Join(sets, ",\n\t"), nil
+}
+
+// jsonPathExpr writes a path as a SQL/JSON path expression such as
+// $."address"."city", numeric keys index arrays
+func jsonPathExpr(path []string) string {
+	var b strings.Builder
+
+	b.WriteString("$")
+
+	for _, key := range path {
+		_, err := strconv.Atoi(key)
+		if err == nil {
+			b.WriteString("[" + key + "]")
+			continue
+		}
+
+		b.WriteString(".\"" + strings.ReplaceAll(key, "\"", "\\\"") + "\"")
+	}
+
+	return b.String()
+}
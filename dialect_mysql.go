@@ -0,0 +1,192 @@
+package scaffold
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MySQLDialect writes SQL for MySQL and MariaDB, connections need
+// parseTime=true so date columns scan into time values, array cells are
+// stored in JSON columns and keys come back through LAST_INSERT_ID()
+type MySQLDialect struct{}
+
+// Name of the dialect
+func (d *MySQLDialect) Name() string {
+	return "mysql"
+}
+
+// Placeholder for the nth argument
+func (d *MySQLDialect) Placeholder(n int) string {
+	return "?"
+}
+
+// Quote an identifier with backticks
+func (d *MySQLDialect) Quote(name string) string {
+	return quoteIdent(name, "`")
+}
+
+// True literal
+func (d *MySQLDialect) True() string {
+	return "TRUE"
+}
+
+// False literal
+func (d *MySQLDialect) False() string {
+	return "FALSE"
+}
+
+// MaxArgs bound by one statement
+func (d *MySQLDialect) MaxArgs() int {
+	return 65535
+}
+
+// SupportsReturning clauses, keys are read with LAST_INSERT_ID() instead
+func (d *MySQLDialect) SupportsReturning() bool {
+	return false
+}
+
+// Array binds the values as a JSON document
+func (d *MySQLDialect) Array(t CellType, values []interface{}, bind func(interface{}) string) (string, error) {
+	return encodeJSONArray(values, bind)
+}
+
+// NullArray renders NULL
+func (d *MySQLDialect) NullArray(t CellType) string {
+	return "NULL"
+}
+
+// NativeArrays are not supported
+func (d *MySQLDialect) NativeArrays() bool {
+	return false
+}
+
+// Upsert renders ON DUPLICATE KEY UPDATE, the conflict columns are implied
+// by the table's unique keys, doing nothing assigns a column to itself
+func (d *MySQLDialect) Upsert(conflict []string, updates []string, fields []string) (string, error) {
+	if len(updates) == 0 {
+		target := fields
+		if len(conflict) > 0 {
+			target = conflict
+		}
+
+		if len(target) == 0 {
+			return "", &ValidationError{Reason: "upsert needs at least one column"}
+		}
+
+		return "ON DUPLICATE KEY UPDATE " + d.Quote(target[0]) + " = " + d.Quote(target[0]), nil
+	}
+
+	sets := make([]string, 0, len(updates))
+	for _, u := range updates {
+		sets = append(sets, d.Quote(u)+" = VALUES("+d.Quote(u)+")")
+	}
+
+	return "ON DUPLICATE KEY UPDATE\n\t" + strings.Join(sets, ",\n\t"), nil
+}
+
+// DefaultValues renders an empty column and value list since MySQL has
+// no DEFAULT VALUES
+func (d *MySQLDialect) DefaultValues() string {
+	return "() VALUES ()"
+}
+
+// ArrayHas renders JSON_CONTAINS over the JSON document
+func (d *MySQLDialect) ArrayHas(field string, placeholder string) string {
+	return "JSON_CONTAINS(" + field + ", JSON_ARRAY(" + placeholder + "))"
+}
+
+// ILike lowers both sides since MySQL has no ILIKE
+func (d *MySQLDialect) ILike(field string, placeholder string) string {
+	return "LOWER(" + field + ") LIKE LOWER(" + placeholder + ")"
+}
+
+// LimitOffset renders LIMIT and OFFSET, MySQL needs a LIMIT before any
+// OFFSET so the largest row count stands in for no limit
+func (d *MySQLDialect) LimitOffset(limit int, offset int) string {
+	if limit < 0 && offset < 0 {
+		return ""
+	}
+
+	s := "LIMIT 18446744073709551615"
+	if limit >= 0 {
+		s = "LIMIT " + strconv.Itoa(limit)
+	}
+
+	if offset >= 0 {
+		s += "\nOFFSET " + strconv.Itoa(offset)
+	}
+
+	return s
+}
+
+// ColumnType for a cell
+func (d *MySQLDialect) ColumnType(t CellType) string {
+	return columnType(d.Name(), t)
+}
+
+// CellType for a driver type name
+func (d *MySQLDialect) CellType(dbType string) (CellType, bool) {
+	return cellTypeFor(dbType)
+}
+
+// JSONPath renders JSON_EXTRACT unquoted to text
+func (d *MySQLDialect) JSONPath(field string, path []string, bind func(interface{}) string) string {
+	return "JSON_UNQUOTE(JSON_EXTRACT(" + field + ", " + bind(jsonPathExpr(path)) + "))"
+}
+
+// JSONContains renders JSON_CONTAINS
+func (d *MySQLDialect) JSONContains(field string, placeholder string) (string, error) {
+	return "JSON_CONTAINS(" + field + ", " + placeholder + ")", nil
+}
+
+// BindTime writes columns without a time zone as the wall clock of loc,
+// the driver sends values in its own loc setting which is expected to be
+// UTC, so zoned values are stored in UTC
+func (d *MySQLDialect) BindTime(v time.Time, zoned bool, loc *time.Location) interface{} {
+	if zoned {
+		return v.UTC()
+	}
+
+	return wallClock(v.In(loc), time.UTC)
+}
+
+// ClassifyError reads the error number of a MySQL error message, the
+// driver is not imported so only its message format is relied on
+func (d *MySQLDialect) ClassifyError(err error) (error, string, []string) {
+	if err == nil {
+		return nil, "", nil
+	}
+
+	msg := err.Error()
+
+	switch {
+	case strings.HasPrefix(msg, "Error 1062"):
+		return ErrUniqueViolation, between(msg, "for key '", "'"), nil
+	case strings.HasPrefix(msg, "Error 1451"), strings.HasPrefix(msg, "Error 1452"):
+		return ErrForeignKeyViolation, between(msg, "CONSTRAINT `", "`"), nil
+	case strings.HasPrefix(msg, "Error 1048"):
+		return ErrNotNullViolation, "", []string{between(msg, "Column '", "'")}
+	case strings.HasPrefix(msg, "Error 1213"), strings.HasPrefix(msg, "Error 1205"):
+		return ErrSerialization, "", nil
+	}
+
+	return nil, "", nil
+}
+
+// between returns the text of s found between from and to
+func between(s string, from string, to string) string {
+	i := strings.Index(s, from)
+	if i < 0 {
+		return ""
+	}
+
+	s = s[i+len(from):]
+
+	j := strings.Index(s, to)
+	if j < 0 {
+		return s
+	}
+
+	return s[:j]
+}
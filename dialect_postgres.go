@@ -0,0 +1,173 @@
+package scaffold
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// PostgresDialect writes SQL for postgres
+type PostgresDialect struct{}
+
+// Name of the dialect
+func (d *PostgresDialect) Name() string {
+	return "postgres"
+}
+
+// Placeholder for the nth argument
+func (d *PostgresDialect) Placeholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// Quote an identifier
+func (d *PostgresDialect) Quote(name string) string {
+	return quoteIdent(name, `"`)
+}
+
+// True literal
+func (d *PostgresDialect) True() string {
+	return "TRUE"
+}
+
+// False literal
+func (d *PostgresDialect) False() string {
+	return "FALSE"
+}
+
+// MaxArgs bound by one statement
+func (d *PostgresDialect) MaxArgs() int {
+	return 65535
+}
+
+// SupportsReturning clauses
+func (d *PostgresDialect) SupportsReturning() bool {
+	return true
+}
+
+// Array renders an ARRAY literal cast to the cell type
+func (d *PostgresDialect) Array(t CellType, values []interface{}, bind func(interface{}) string) (string, error) {
+	if !isArrayCell(t) {
+		return "", errors.New("not an array cell type")
+	}
+
+	cast := strings.ToLower(d.ColumnType(t))
+
+	placeholders := make([]string, 0, len(values))
+
+	for _, v := range values {
+		placeholders = append(placeholders, bind(v))
+	}
+
+	return "ARRAY[" + strings.Join(placeholders, ",") + "]::" + cast, nil
+}
+
+// NullArray renders a NULL cast to the cell type
+func (d *PostgresDialect) NullArray(t CellType) string {
+	if !isArrayCell(t) {
+		return "NULL"
+	}
+
+	return "NULL::" + strings.ToLower(d.ColumnType(t))
+}
+
+// NativeArrays are supported
+func (d *PostgresDialect) NativeArrays() bool {
+	return true
+}
+
+// Upsert renders ON CONFLICT
+func (d *PostgresDialect) Upsert(conflict []string, updates []string, fields []string) (string, error) {
+	return onConflict(d, conflict, updates)
+}
+
+// DefaultValues renders DEFAULT VALUES
+func (d *PostgresDialect) DefaultValues() string {
+	return "DEFAULT VALUES"
+}
+
+// ArrayHas renders ANY over the array
+func (d *PostgresDialect) ArrayHas(field string, placeholder string) string {
+	return placeholder + " = ANY(" + field + ")"
+}
+
+// ILike renders a case insensitive LIKE
+func (d *PostgresDialect) ILike(field string, placeholder string) string {
+	return field + " ILIKE " + placeholder
+}
+
+// LimitOffset renders LIMIT and OFFSET
+func (d *PostgresDialect) LimitOffset(limit int, offset int) string {
+	parts := make([]string, 0, 2)
+
+	if limit >= 0 {
+		parts = append(parts, "LIMIT "+strconv.Itoa(limit))
+	}
+
+	if offset >= 0 {
+		parts = append(parts, "OFFSET "+strconv.Itoa(offset))
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// ColumnType for a cell
+func (d *PostgresDialect) ColumnType(t CellType) string {
+	return columnType(d.Name(), t)
+}
+
+// CellType for a driver type name
+func (d *PostgresDialect) CellType(dbType string) (CellType, bool) {
+	return cellTypeFor(dbType)
+}
+
+// JSONPath renders #>> with the path bound as a text array
+func (d *PostgresDialect) JSONPath(field string, path []string, bind func(interface{}) string) string {
+	return "(" + field + " #>> " + bind(pq.Array(path)) + ")"
+}
+
+// JSONContains renders @>
+func (d *PostgresDialect) JSONContains(field string, placeholder string) (string, error) {
+	return field + " @> " + placeholder + "::jsonb", nil
+}
+
+// BindTime converts the value to loc, the offset is ignored by
+// columns without a time zone so they keep the local wall clock
+func (d *PostgresDialect) BindTime(v time.Time, zoned bool, loc *time.Location) interface{} {
+	return v.In(loc)
+}
+
+// ClassifyError reads the SQLSTATE of a pq error
+func (d *PostgresDialect) ClassifyError(err error) (error, string, []string) {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return nil, "", nil
+	}
+
+	switch pqErr.Code {
+	case "23505":
+		return ErrUniqueViolation, pqErr.Constraint, detailColumns(pqErr.Detail)
+	case "23503":
+		return ErrForeignKeyViolation, pqErr.Constraint, detailColumns(pqErr.Detail)
+	case "23502":
+		return ErrNotNullViolation, pqErr.Constraint, []string{pqErr.Column}
+	case "40001", "40P01":
+		return ErrSerialization, "", nil
+	}
+
+	return nil, "", nil
+}
+
+// detailColumns reads the columns out of a detail such as
+// "Key (a, b)=(1, 2) already exists."
+func detailColumns(detail string) []string {
+	start := strings.Index(detail, "(")
+	end := strings.Index(detail, ")=")
+	if start < 0 || end < start {
+		return nil
+	}
+
+	return splitColumns(detail[start+1 : end])
+}
@@ -0,0 +1,165 @@
+package scaffold
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SQLiteDialect writes SQL for sqlite
+type SQLiteDialect struct{}
+
+// Name of the dialect
+func (d *SQLiteDialect) Name() string {
+	return "sqlite"
+}
+
+// Placeholder for the nth argument
+func (d *SQLiteDialect) Placeholder(n int) string {
+	return "?"
+}
+
+// Quote an identifier
+func (d *SQLiteDialect) Quote(name string) string {
+	return quoteIdent(name, `"`)
+}
+
+// True literal
+func (d *SQLiteDialect) True() string {
+	return "1"
+}
+
+// False literal
+func (d *SQLiteDialect) False() string {
+	return "0"
+}
+
+// MaxArgs bound by one statement, the limit of older sqlite builds
+func (d *SQLiteDialect) MaxArgs() int {
+	return 999
+}
+
+// SupportsReturning clauses, available since sqlite 3.35
+func (d *SQLiteDialect) SupportsReturning() bool {
+	return true
+}
+
+// Array binds the values as JSON text since sqlite has no array type
+func (d *SQLiteDialect) Array(t CellType, values []interface{}, bind func(interface{}) string) (string, error) {
+	return encodeJSONArray(values, bind)
+}
+
+// NullArray renders NULL
+func (d *SQLiteDialect) NullArray(t CellType) string {
+	return "NULL"
+}
+
+// NativeArrays are not supported, arrays are stored as JSON text
+func (d *SQLiteDialect) NativeArrays() bool {
+	return false
+}
+
+// Upsert renders ON CONFLICT
+func (d *SQLiteDialect) Upsert(conflict []string, updates []string, fields []string) (string, error) {
+	return onConflict(d, conflict, updates)
+}
+
+// DefaultValues renders DEFAULT VALUES
+func (d *SQLiteDialect) DefaultValues() string {
+	return "DEFAULT VALUES"
+}
+
+// ArrayHas renders a json_each subquery over the JSON text
+func (d *SQLiteDialect) ArrayHas(field string, placeholder string) string {
+	return "EXISTS (SELECT 1 FROM json_each(" + field + ") WHERE json_each.value = " + placeholder + ")"
+}
+
+// ILike lowers both sides since sqlite has no ILIKE
+func (d *SQLiteDialect) ILike(field string, placeholder string) string {
+	return "LOWER(" + field + ") LIKE LOWER(" + placeholder + ")"
+}
+
+// LimitOffset renders LIMIT and OFFSET, sqlite needs a LIMIT before any
+// OFFSET so -1 stands in for no limit
+func (d *SQLiteDialect) LimitOffset(limit int, offset int) string {
+	if limit < 0 && offset < 0 {
+		return ""
+	}
+
+	if limit < 0 {
+		limit = -1
+	}
+
+	s := "LIMIT " + strconv.Itoa(limit)
+
+	if offset >= 0 {
+		s += "\nOFFSET " + strconv.Itoa(offset)
+	}
+
+	return s
+}
+
+// ColumnType for a cell
+func (d *SQLiteDialect) ColumnType(t CellType) string {
+	return columnType(d.Name(), t)
+}
+
+// CellType for a declared column type, size suffixes such as VARCHAR(20)
+// are ignored
+func (d *SQLiteDialect) CellType(dbType string) (CellType, bool) {
+	name := strings.ToUpper(strings.TrimSpace(dbType))
+
+	i := strings.Index(name, "(")
+	if i >= 0 {
+		name = strings.TrimSpace(name[:i])
+	}
+
+	return cellTypeFor(name)
+}
+
+// JSONPath renders json_extract
+func (d *SQLiteDialect) JSONPath(field string, path []string, bind func(interface{}) string) string {
+	return "json_extract(" + field + ", " + bind(jsonPathExpr(path)) + ")"
+}
+
+// JSONContains is not supported
+func (d *SQLiteDialect) JSONContains(field string, placeholder string) (string, error) {
+	return "", errors.New("JSON containment is not supported by sqlite")
+}
+
+// BindTime writes ISO-8601 text, the driver would otherwise pick its own
+// layout, zoned columns are written in UTC so the text sorts in time order
+// whatever the offset of loc, other columns hold the wall clock of loc
+func (d *SQLiteDialect) BindTime(v time.Time, zoned bool, loc *time.Location) interface{} {
+	if zoned {
+		return v.UTC().Format(isoDatetimeTZ)
+	}
+
+	return v.In(loc).Format(isoDatetime)
+}
+
+// ClassifyError reads the message of a sqlite error, the driver is not
+// imported so any sqlite driver reporting the standard messages works
+func (d *SQLiteDialect) ClassifyError(err error) (error, string, []string) {
+	if err == nil {
+		return nil, "", nil
+	}
+
+	msg := err.Error()
+
+	if i := strings.Index(msg, "UNIQUE constraint failed:"); i >= 0 {
+		return ErrUniqueViolation, "", splitColumns(msg[i+len("UNIQUE constraint failed:"):])
+	}
+	if i := strings.Index(msg, "NOT NULL constraint failed:"); i >= 0 {
+		return ErrNotNullViolation, "", splitColumns(msg[i+len("NOT NULL constraint failed:"):])
+	}
+	if strings.Contains(msg, "FOREIGN KEY constraint failed") {
+		return ErrForeignKeyViolation, "", nil
+	}
+	if strings.Contains(msg, "database is locked") || strings.Contains(msg, "database table is locked") {
+		return ErrSerialization, "", nil
+	}
+
+	return nil, "", nil
+}
@@ -0,0 +1,112 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotFound is returned when no row matches a primary key
+var ErrNotFound = errors.New("row not found")
+
+// ErrUniqueViolation is matched by errors.Is when an insert or update
+// breaks a unique or primary key constraint
+var ErrUniqueViolation = errors.New("unique violation")
+
+// ErrForeignKeyViolation is matched by errors.Is when a statement breaks a
+// foreign key constraint
+var ErrForeignKeyViolation = errors.New("foreign key violation")
+
+// ErrNotNullViolation is matched by errors.Is when NULL is written to a
+// NOT NULL column
+var ErrNotNullViolation = errors.New("not null violation")
+
+// ErrSerialization is matched by errors.Is when the database aborted the
+// statement over a conflicting transaction, retrying may succeed
+var ErrSerialization = errors.New("serialization failure")
+
+// ErrTypeMismatch is matched by errors.Is when a cell is read or set as a
+// type it does not hold
+var ErrTypeMismatch = errors.New("type mismatch")
+
+// ErrUnknownType is matched by errors.Is when GetRaw meets a column type
+// it has no cell type for and strict types are on
+var ErrUnknownType = errors.New("unknown column type")
+
+// QueryError wraps the driver error of a failed statement with the table
+// and the SQL that was run, Kind is one of the Err values above when the
+// failure could be classified
+type QueryError struct {
+	Op         string
+	Table      string
+	SQL        string
+	Kind       error
+	Constraint string
+	Columns    []string
+	Err        error
+}
+
+// Error satisfies the error interface
+func (e *QueryError) Error() string {
+	msg := "Failure to " + e.Op
+	if e.Table != "" {
+		msg += " on " + e.Table
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	} else if e.Kind != nil {
+		msg += ": " + e.Kind.Error()
+	}
+	return msg
+}
+
+// Unwrap gives errors.As access to the driver error
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches the classified kind of the error
+func (e *QueryError) Is(target error) bool {
+	return e.Kind != nil && e.Kind == target
+}
+
+// queryError wraps a driver error, classifying it with the dialect
+func (s *DB) queryError(op string, table string, q string, err error) error {
+	e := &QueryError{Op: op, Table: table, SQL: q, Err: err}
+
+	e.Kind, e.Constraint, e.Columns = s.dialect.ClassifyError(err)
+
+	return e
+}
+
+// typeMismatch reports a cell read or set as a type it does not hold
+func typeMismatch(c *Cell, want string) error {
+	return fmt.Errorf("%w: cell %s is not %s", ErrTypeMismatch, c.Name, want)
+}
+
+// valueMismatch reports a value of the wrong Go type handed to a cell type,
+// such as a cell holding data of another type
+func valueMismatch(v interface{}, want string) error {
+	return fmt.Errorf("%w: %T is not %s", ErrTypeMismatch, v, want)
+}
+
+// splitColumns parses a column list such as "users.a, users.b" dropping
+// the table prefix
+func splitColumns(list string) []string {
+	columns := make([]string, 0)
+
+	for _, col := range strings.Split(list, ",") {
+		col = strings.TrimSpace(col)
+
+		i := strings.LastIndex(col, ".")
+		if i >= 0 {
+			col = col[i+1:]
+		}
+
+		if col != "" {
+			columns = append(columns, col)
+		}
+	}
+
+	return columns
+}
@@ -4,6 +4,9 @@ go 1.15
 
 require (
 	github.com/davecgh/go-spew v1.1.1
+	github.com/go-sql-driver/mysql v1.5.0
 	github.com/lib/pq v1.9.0
+	github.com/shopspring/decimal v1.4.0
+	github.com/tidwall/gjson v1.6.1
 	github.com/tidwall/sjson v1.1.2
 )
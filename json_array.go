@@ -0,0 +1,56 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// jsonArray scans an array stored as JSON text into an array cell
+type jsonArray struct {
+	Valid *bool
+	Value interface{}
+}
+
+// Scan JSON->Array
+func (x *jsonArray) Scan(data interface{}) error {
+	switch v := data.(type) {
+	case nil:
+		*x.Valid = false
+		return nil
+	case []byte:
+		*x.Valid = true
+		return json.Unmarshal(v, x.Value)
+	case string:
+		*x.Valid = true
+		return json.Unmarshal([]byte(v), x.Value)
+	}
+	return errors.New("Incompatible type")
+}
+
+// jsonTargeter is implemented by array cells that can be read from JSON
+type jsonTargeter interface {
+	JSONTarget() interface{}
+}
+
+// encodeJSONArray binds an array cell value as JSON text
+func encodeJSONArray(values []interface{}, bind func(interface{}) string) (string, error) {
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+
+	return bind(string(b)), nil
+}
+
+// scanTarget is the scannable target for a cell in a dialect, arrays are
+// read from JSON text when the dialect has no native arrays
+func scanTarget(d Dialect, c *Cell) interface{} {
+	if isArrayCell(c.Type) && !d.NativeArrays() {
+		j, ok := c.Data.(jsonTargeter)
+		if ok {
+			return j.JSONTarget()
+		}
+	}
+
+	return c.CellTarget()
+}
@@ -0,0 +1,165 @@
+package scaffold
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// keyQuery builds a query matching one row by its primary key
+func (t *Table) keyQuery(keys []interface{}) (Query, error) {
+	q := Query{Limit: -1, Offset: -1}
+
+	names := t.primaryKeys()
+	if len(names) == 0 {
+		return q, &ValidationError{Field: t.Name, Reason: "no primary key declared"}
+	}
+
+	if len(keys) != len(names) {
+		return q, &ValidationError{Field: t.Name, Reason: "primary key has " + strconv.Itoa(len(names)) + " cells, got " + strconv.Itoa(len(keys)) + " values"}
+	}
+
+	for i, name := range names {
+		q.Filters = append(q.Filters, Filter{Field: name, Comparison: Eq, Value: keys[i]})
+	}
+
+	return q, nil
+}
+
+// rowKeys reads the primary key values held by a row
+func (t *Table) rowKeys(row *Row) ([]interface{}, error) {
+	keys := make([]interface{}, 0)
+
+	for _, name := range t.primaryKeys() {
+		c, ok := row.Cells[name]
+		if !ok || c.Data == nil {
+			return nil, &ValidationError{Field: name, Reason: "primary key not set on row"}
+		}
+
+		v, err := c.GetValue()
+		if err != nil {
+			return nil, &ValidationError{Field: name, Reason: "primary key not set on row"}
+		}
+
+		keys = append(keys, v)
+	}
+
+	return keys, nil
+}
+
+// Get fetches a single row by its primary key values, in declaration order
+func (t *Table) Get(keys ...interface{}) (*Row, error) {
+	return t.GetContext(context.Background(), keys...)
+}
+
+// GetContext works as Get, the context cancels the query
+func (t *Table) GetContext(ctx context.Context, keys ...interface{}) (*Row, error) {
+	q, err := t.keyQuery(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	q.Limit = 1
+
+	rows, err := t.GetRowsContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows.Rows) == 0 {
+		return nil, &QueryError{Op: "find row", Table: t.Name, Kind: ErrNotFound}
+	}
+
+	return rows.Rows[0], nil
+}
+
+// Exists reports whether a row with the primary key values exists
+func (t *Table) Exists(keys ...interface{}) (bool, error) {
+	return t.ExistsContext(context.Background(), keys...)
+}
+
+// ExistsContext works as Exists, the context cancels the query
+func (t *Table) ExistsContext(ctx context.Context, keys ...interface{}) (bool, error) {
+	_, err := t.GetContext(ctx, keys...)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// UpdateByKey writes the cells set on row to the row with the primary key
+// values, when no keys are given the key cells of row itself are used
+func (t *Table) UpdateByKey(row *Row, keys ...interface{}) (int64, error) {
+	return t.UpdateByKeyContext(context.Background(), row, keys...)
+}
+
+// UpdateByKeyContext works as UpdateByKey, the context cancels the query
+func (t *Table) UpdateByKeyContext(ctx context.Context, row *Row, keys ...interface{}) (int64, error) {
+	if len(keys) == 0 {
+		var err error
+
+		keys, err = t.rowKeys(row)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	q, err := t.keyQuery(keys)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.UpdateContext(ctx, row, q)
+}
+
+// UpdateByKeyReturning works as UpdateByKey but gives back the updated row
+func (t *Table) UpdateByKeyReturning(row *Row, keys ...interface{}) (*Row, error) {
+	return t.UpdateByKeyReturningContext(context.Background(), row, keys...)
+}
+
+// UpdateByKeyReturningContext works as UpdateByKeyReturning, the context cancels the query
+func (t *Table) UpdateByKeyReturningContext(ctx context.Context, row *Row, keys ...interface{}) (*Row, error) {
+	if len(keys) == 0 {
+		var err error
+
+		keys, err = t.rowKeys(row)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	q, err := t.keyQuery(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := t.UpdateReturningContext(ctx, row, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows.Rows) == 0 {
+		return nil, &QueryError{Op: "find row", Table: t.Name, Kind: ErrNotFound}
+	}
+
+	return rows.Rows[0], nil
+}
+
+// DeleteByKey removes the row with the primary key values
+func (t *Table) DeleteByKey(keys ...interface{}) (int64, error) {
+	return t.DeleteByKeyContext(context.Background(), keys...)
+}
+
+// DeleteByKeyContext works as DeleteByKey, the context cancels the query
+func (t *Table) DeleteByKeyContext(ctx context.Context, keys ...interface{}) (int64, error) {
+	q, err := t.keyQuery(keys)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.DeleteContext(ctx, q)
+}
@@ -0,0 +1,156 @@
+//go:build mariadb
+// +build mariadb
+
+package scaffold
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// Runs against a MariaDB or MySQL server, for example
+//
+//	docker run -d -p 3306:3306 -e MARIADB_ROOT_PASSWORD=secret -e MARIADB_DATABASE=scaffold mariadb
+//	SCAFFOLD_MARIADB_DSN='root:secret@tcp(127.0.0.1:3306)/scaffold?parseTime=true' go test -tags mariadb ./...
+
+// mariaDB connects to the server named by SCAFFOLD_MARIADB_DSN
+func mariaDB(t *testing.T) *DB {
+	dsn := os.Getenv("SCAFFOLD_MARIADB_DSN")
+	if dsn == "" {
+		t.Skip("SCAFFOLD_MARIADB_DSN is not set")
+	}
+
+	conn, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	return New(conn, new(MySQLDialect))
+}
+
+// mariaTable creates a fresh table
+func mariaTable(t *testing.T, db *DB, name string, cells []*Cell) *Table {
+	_, err := db.Exec("DROP TABLE IF EXISTS " + db.Dialect().Quote(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tb := db.NewTable(name, cells)
+
+	err = db.CreateTable(tb)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { db.Exec("DROP TABLE IF EXISTS " + db.Dialect().Quote(name)) })
+
+	return tb
+}
+
+func TestMariaDBStringKey(t *testing.T) {
+	db := mariaDB(t)
+
+	tb := mariaTable(t, db, "scaffold_users", []*Cell{
+		{Name: "email", Type: CellString, PrimaryKey: true},
+		{Name: "name", Type: CellString},
+		{Name: "tags", Type: CellStringArray},
+		{Name: "age", Type: CellInt},
+	})
+
+	row := tb.NewRow()
+	row.Cells["email"].SetString("a@example.com")
+	row.Cells["name"].SetString("Ann")
+	row.Cells["tags"].Data = &SQLStringArray{Valid: true, Value: []string{"x", "y"}}
+	row.Cells["age"].SetInt(30)
+
+	keys, err := tb.Insert(row, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(keys, []interface{}{"a@example.com"}) {
+		t.Errorf("insert returned %v", keys)
+	}
+
+	_, err = tb.Insert(row, "")
+	if !errors.Is(err, ErrUniqueViolation) {
+		t.Errorf("duplicate insert returned %v", err)
+	}
+
+	row.Cells["tags"].Data = &SQLStringArray{Valid: true, Value: []string{"z"}}
+
+	_, err = tb.Upsert(row, []string{"email"}, []string{"tags"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := tb.Get("a@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tags, err := got.Cells["tags"].StringArray()
+	if err != nil || !reflect.DeepEqual(tags, []string{"z"}) {
+		t.Errorf("upserted tags %v, err %v", tags, err)
+	}
+
+	rows, err := tb.GetRows(Query{
+		Filters: []Filter{{Field: "tags", Comparison: Has, Value: "z"}},
+		Orders:  []Order{{Field: "age", Direction: "desc"}},
+		Limit:   -1,
+		Offset:  -1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows.Rows) != 1 {
+		t.Errorf("filter matched %d rows", len(rows.Rows))
+	}
+
+	n, err := tb.Delete(Query{Filters: []Filter{{Field: "email", Comparison: Eq, Value: "a@example.com"}}})
+	if err != nil || n != 1 {
+		t.Errorf("deleted %d rows, err %v", n, err)
+	}
+}
+
+func TestMariaDBAutoIncrement(t *testing.T) {
+	db := mariaDB(t)
+
+	tb := mariaTable(t, db, "scaffold_items", []*Cell{
+		{Name: "id", Type: CellInt, PrimaryKey: true, SQL: "BIGINT AUTO_INCREMENT"},
+		{Name: "label", Type: CellString},
+	})
+
+	for want := int64(1); want <= 2; want++ {
+		row := tb.NewRow()
+		row.Cells["label"].SetString("item")
+
+		got, err := tb.InsertReturning(row)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		id, err := got.Cells["id"].Int()
+		if err != nil || id != want {
+			t.Errorf("inserted id %d, want %d, err %v", id, want, err)
+		}
+	}
+
+	got, err := tb.InsertReturning(tb.NewRow())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := got.Cells["id"].Int()
+	if err != nil || id != 3 {
+		t.Errorf("defaults only insert got id %d, err %v", id, err)
+	}
+}
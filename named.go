@@ -0,0 +1,155 @@
+package scaffold
+
+import (
+	"errors"
+	"strings"
+)
+
+// Params binds named parameters, passed as the only argument to Exec or
+// Query each :name in the SQL is replaced by the dialect placeholder for
+// its value, slices expand to a parenthesised list for IN
+type Params map[string]interface{}
+
+// bindArgs renders q for the statement, named parameters are rewritten
+// when args is a single Params, any other args are bound as they are
+func bindArgs(st *statement, q string, args []interface{}) (string, error) {
+	if len(args) == 1 {
+		params, ok := args[0].(Params)
+		if ok {
+			return bindNamed(st, q, params)
+		}
+	}
+
+	for _, arg := range args {
+		st.bindRaw(arg)
+	}
+
+	return q, nil
+}
+
+// bindNamed replaces each :name outside of quotes, comments and postgres
+// dollar quoted bodies with a placeholder, casts such as ::text are left
+// alone
+func bindNamed(st *statement, q string, params Params) (string, error) {
+	var b strings.Builder
+
+	var quote byte
+
+	for i := 0; i < len(q); i++ {
+		c := q[i]
+
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			b.WriteByte(c)
+			continue
+		}
+
+		end := skipLiteral(q, i)
+		if end > i {
+			b.WriteString(q[i:end])
+			i = end - 1
+			continue
+		}
+
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == ':' && i+1 < len(q) && q[i+1] == ':':
+			b.WriteString("::")
+			i++
+			continue
+		case c == ':' && i+1 < len(q) && isNameStart(q[i+1]):
+			j := i + 1
+			for j < len(q) && isNamePart(q[j]) {
+				j++
+			}
+
+			name := q[i+1 : j]
+
+			v, ok := params[name]
+			if !ok {
+				return "", errors.New("missing named parameter " + name)
+			}
+
+			p, err := bindParam(st, v)
+			if err != nil {
+				return "", errors.New("named parameter " + name + ": " + err.Error())
+			}
+
+			b.WriteString(p)
+			i = j - 1
+			continue
+		}
+
+		b.WriteByte(c)
+	}
+
+	return b.String(), nil
+}
+
+// skipLiteral returns the end of a comment or dollar quoted body starting
+// at i, or i when none starts there, unterminated ones run to the end of q
+func skipLiteral(q string, i int) int {
+	rest := q[i:]
+
+	switch {
+	case strings.HasPrefix(rest, "--"):
+		end := strings.IndexByte(rest, '\n')
+		if end < 0 {
+			return len(q)
+		}
+
+		return i + end
+	case strings.HasPrefix(rest, "/*"):
+		end := strings.Index(rest[2:], "*/")
+		if end < 0 {
+			return len(q)
+		}
+
+		return i + 2 + end + 2
+	case rest[0] == '$' && (i == 0 || !isNamePart(q[i-1])):
+		j := 1
+		if j < len(rest) && isNameStart(rest[j]) {
+			for j < len(rest) && isNamePart(rest[j]) {
+				j++
+			}
+		}
+
+		if j >= len(rest) || rest[j] != '$' {
+			return i
+		}
+
+		tag := rest[:j+1]
+
+		end := strings.Index(rest[len(tag):], tag)
+		if end < 0 {
+			return len(q)
+		}
+
+		return i + len(tag) + end + len(tag)
+	}
+
+	return i
+}
+
+// bindParam binds a named value, expanding slices
+func bindParam(st *statement, v interface{}) (string, error) {
+	_, isList := listValues(v)
+	if isList {
+		return st.Bind(v)
+	}
+
+	return st.bindRaw(v), nil
+}
+
+// isNameStart reports whether c can start a parameter name
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isNamePart reports whether c can continue a parameter name
+func isNamePart(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,52 @@
+package scaffold
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBindNamed(t *testing.T) {
+	cases := []struct {
+		name string
+		q    string
+		want string
+		args []interface{}
+	}{
+		{"plain", "SELECT * FROM t WHERE a = :a AND b = :b_2", "SELECT * FROM t WHERE a = $1 AND b = $2", []interface{}{1, "x"}},
+		{"cast", "SELECT :a::text", "SELECT $1::text", []interface{}{1}},
+		{"quotes", `SELECT ':a', ":a", ` + "`:a`" + `, :a`, `SELECT ':a', ":a", ` + "`:a`" + `, $1`, []interface{}{1}},
+		{"line comment", "SELECT :a -- :zz\nFROM t", "SELECT $1 -- :zz\nFROM t", []interface{}{1}},
+		{"trailing comment", "SELECT :a -- :zz", "SELECT $1 -- :zz", []interface{}{1}},
+		{"block comment", "SELECT /* :zz */ :a", "SELECT /* :zz */ $1", []interface{}{1}},
+		{"dollar quote", "SELECT $$ :zz $$, :a", "SELECT $$ :zz $$, $1", []interface{}{1}},
+		{"tagged dollar quote", "SELECT $fn$ :zz $$ :zz $fn$, :a", "SELECT $fn$ :zz $$ :zz $fn$, $1", []interface{}{1}},
+		{"list", "SELECT * FROM t WHERE b IN :list AND a = :a", "SELECT * FROM t WHERE b IN ($1, $2) AND a = $3", []interface{}{"x", "y", 1}},
+	}
+
+	params := Params{"a": 1, "b_2": "x", "list": []string{"x", "y"}}
+
+	for _, c := range cases {
+		st := newStatement(new(PostgresDialect))
+
+		got, err := bindNamed(st, c.q, params)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+
+		if got != c.want {
+			t.Errorf("%s:\n got %s\nwant %s", c.name, got, c.want)
+		}
+
+		if !reflect.DeepEqual(st.Args(), c.args) {
+			t.Errorf("%s: args %v, want %v", c.name, st.Args(), c.args)
+		}
+	}
+}
+
+func TestBindNamedMissing(t *testing.T) {
+	_, err := bindNamed(newStatement(new(PostgresDialect)), "SELECT :a, :zz", Params{"a": 1})
+	if err == nil || err.Error() != "missing named parameter zz" {
+		t.Errorf("got %v", err)
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"bytes"
 )
 
-// Filter a query
+// Filter a query, Path compares the value at a dotted path inside a JSON
+// field, such as "address.city", instead of the field itself
 type Filter struct {
 	Operator   string
 	Field      string
-	Comparison string
-	Value      string
+	Path       string
+	Comparison Comparison
+	Value      interface{}
 	Group      []Filter
 }
 
@@ -27,17 +29,31 @@ type Query struct {
 	Offset  int
 }
 
-// ToBytes converts a query to a byte array text representation
-func (q *Query) ToBytes() ([]byte, error) {
+// ToBytes converts a query to a byte array text representation along with
+// the arguments bound to its placeholders
+func (q *Query) ToBytes() ([]byte, []interface{}, error) {
+	return q.ToBytesFor(std.dialect)
+}
+
+// ToBytesFor works as ToBytes for a given dialect
+func (q *Query) ToBytesFor(d Dialect) ([]byte, []interface{}, error) {
 	var b bytes.Buffer
 
+	err := q.Validate(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	st := newStatement(d)
+
 	templateVars := make(map[string]interface{}, 0)
 	templateVars["query"] = q
+	templateVars["stmt"] = st
 
-	err := tmpl.ExecuteTemplate(&b, "query", templateVars)
+	err = render(&b, "query", templateVars)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return b.Bytes(), nil
+	return b.Bytes(), st.Args(), nil
 }
@@ -0,0 +1,232 @@
+package scaffold
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// CellTypeDef describes a cell type, the built in types are registered the
+// same way as types added with RegisterCellType
+type CellTypeDef struct {
+	// Name of the type, used in error messages
+	Name string
+	// New makes an empty value holder that rows are scanned into
+	New func() SQLCell
+	// Array marks types holding a list of values, they are written with the
+	// array syntax of the dialect and stored as JSON where arrays are not
+	// native
+	Array bool
+	// DBTypes are the driver type names read as this type, GetRaw and
+	// Dialect.CellType use them, matching ignores case
+	DBTypes []string
+	// Columns is the DDL type keyed by dialect name, the "" key applies to
+	// dialects without an entry and TEXT is used when neither is set
+	Columns map[string]string
+	// KeyColumns is the DDL type keyed by dialect name for primary key
+	// cells, for dialects that can not index the default type
+	KeyColumns map[string]string
+	// SizedColumns is the DDL type keyed by dialect name for cells setting
+	// Precision, formatted with the precision and scale
+	SizedColumns map[string]string
+	// Bind converts a value before it is bound as an argument, values are
+	// bound as they are when nil
+	Bind func(v interface{}) (interface{}, error)
+	// Null is the typed NULL bound for a cell holding NULL, a NULL string
+	// when nil
+	Null interface{}
+	// JSON renders a value for Row.AsJSON with the options of the DB the
+	// row came from, encoding/json is used when nil
+	JSON func(v interface{}, opts JSONOptions) ([]byte, error)
+	// Unordered lists the dialects whose stored values do not compare in
+	// value order, range comparisons and ORDER BY on the type are refused
+	Unordered []string
+}
+
+// JSONOptions changes how rows render as JSON, set with the methods of DB
+type JSONOptions struct {
+	// DecimalNumbers writes decimals as JSON numbers rather than strings
+	DecimalNumbers bool
+}
+
+// firstCustomCellType leaves room below it for built in types
+const firstCustomCellType CellType = 1 << 10
+
+// cellTypes is the registry of cell types
+var cellTypes = struct {
+	sync.RWMutex
+	defs    map[CellType]*CellTypeDef
+	dbTypes map[string]CellType
+	next    CellType
+}{
+	defs:    make(map[CellType]*CellTypeDef),
+	dbTypes: make(map[string]CellType),
+	next:    firstCustomCellType,
+}
+
+// RegisterCellType adds a cell type and returns the CellType to declare
+// cells with, database type names already taken move to the new type
+func RegisterCellType(def CellTypeDef) CellType {
+	cellTypes.Lock()
+	t := cellTypes.next
+	cellTypes.next++
+	cellTypes.Unlock()
+
+	registerCellType(t, def)
+
+	return t
+}
+
+// registerCellType adds a cell type under a known CellType
+func registerCellType(t CellType, def CellTypeDef) {
+	cellTypes.Lock()
+	defer cellTypes.Unlock()
+
+	cellTypes.defs[t] = &def
+
+	for _, name := range def.DBTypes {
+		cellTypes.dbTypes[strings.ToUpper(name)] = t
+	}
+}
+
+// lookupCellType finds the definition of a cell type
+func lookupCellType(t CellType) (*CellTypeDef, bool) {
+	cellTypes.RLock()
+	defer cellTypes.RUnlock()
+
+	def, ok := cellTypes.defs[t]
+	return def, ok
+}
+
+// cellTypeFor maps a driver type name onto a cell type
+func cellTypeFor(dbType string) (CellType, bool) {
+	cellTypes.RLock()
+	defer cellTypes.RUnlock()
+
+	t, ok := cellTypes.dbTypes[strings.ToUpper(dbType)]
+	return t, ok
+}
+
+// columnType is the DDL type of a cell type in a dialect
+func columnType(dialect string, t CellType) string {
+	def, ok := lookupCellType(t)
+	if !ok {
+		return "TEXT"
+	}
+
+	v, ok := def.Columns[dialect]
+	if ok {
+		return v
+	}
+
+	v, ok = def.Columns[""]
+	if ok {
+		return v
+	}
+
+	return "TEXT"
+}
+
+// keyColumnType is the DDL type of a primary key cell, ok is false when
+// the type has no key specific type in the dialect
+func keyColumnType(dialect string, c *Cell) (string, bool) {
+	def, ok := lookupCellType(c.Type)
+	if !ok || !c.PrimaryKey {
+		return "", false
+	}
+
+	v, ok := def.KeyColumns[dialect]
+	return v, ok
+}
+
+// sizedColumnType is the DDL type of a cell setting Precision, ok is false
+// when the type or dialect takes no size
+func sizedColumnType(dialect string, c *Cell) (string, bool) {
+	def, ok := lookupCellType(c.Type)
+	if !ok || c.Precision <= 0 {
+		return "", false
+	}
+
+	v, ok := def.SizedColumns[dialect]
+	if !ok {
+		return "", false
+	}
+
+	return fmt.Sprintf(v, c.Precision, c.Scale), true
+}
+
+// isUnordered reports whether stored values of a cell type do not compare
+// in value order in a dialect
+func isUnordered(dialect string, t CellType) bool {
+	def, ok := lookupCellType(t)
+	if !ok {
+		return false
+	}
+
+	for _, name := range def.Unordered {
+		if name == dialect {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isArrayCell reports whether a cell type holds a list of values
+func isArrayCell(t CellType) bool {
+	def, ok := lookupCellType(t)
+	return ok && def.Array
+}
+
+// newCellData makes an empty value holder for a cell type, nil when the
+// type cannot be scanned
+func newCellData(t CellType) SQLCell {
+	def, ok := lookupCellType(t)
+	if !ok || def.New == nil {
+		return nil
+	}
+
+	return def.New()
+}
+
+// nullValue is the typed NULL written for a scalar cell without a value
+func nullValue(t CellType) interface{} {
+	def, ok := lookupCellType(t)
+	if !ok || def.Null == nil {
+		return sql.NullString{}
+	}
+
+	return def.Null
+}
+
+// bindCellValue converts a cell value with the Bind of its type
+func bindCellValue(t CellType, v interface{}) (interface{}, error) {
+	def, ok := lookupCellType(t)
+	if !ok || def.Bind == nil {
+		return v, nil
+	}
+
+	return def.Bind(v)
+}
+
+// cellJSON renders a cell value as JSON, arrays without values render as
+// an empty list
+func cellJSON(t CellType, v interface{}, opts JSONOptions) ([]byte, error) {
+	def, ok := lookupCellType(t)
+	if ok && def.JSON != nil {
+		return def.JSON(v, opts)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && def.Array && string(b) == "null" {
+		return []byte("[]"), nil
+	}
+
+	return b, nil
+}
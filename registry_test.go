@@ -0,0 +1,75 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMismatchedDataIsAnError(t *testing.T) {
+	cells := []*Cell{
+		{Name: "doc", Type: CellJSON, Data: &SQLInt{Valid: true, Value: 1}},
+		{Name: "price", Type: CellDecimal, Data: &SQLString{Valid: true, Value: "1.50"}},
+	}
+
+	for _, c := range cells {
+		st := newStatement(new(PostgresDialect))
+
+		_, err := st.BindCell(c)
+		if c.Type == CellJSON && !errors.Is(err, ErrTypeMismatch) {
+			t.Errorf("%s: bind returned %v", c.Name, err)
+		}
+
+		row := &Row{Cells: map[string]*Cell{c.Name: c}}
+
+		_, err = json.Marshal(row)
+		if !errors.Is(err, ErrTypeMismatch) {
+			t.Errorf("%s: marshal returned %v", c.Name, err)
+		}
+	}
+}
+
+func TestDecimalJSONNumbersPerDB(t *testing.T) {
+	cells := []*Cell{{Name: "price", Type: CellDecimal}}
+
+	numbers := New(nil, nil)
+	numbers.SetDecimalJSONNumbers(true)
+
+	want := map[*DB]string{
+		numbers:       `{"price":10.05}`,
+		New(nil, nil): `{"price":"10.05"}`,
+	}
+
+	for db, w := range want {
+		row := db.NewTable("prices", cells).NewRow()
+		row.Cells["price"].Data = &SQLDecimal{Valid: true, Value: decimal.RequireFromString("10.05")}
+
+		got := string(row.AsJSON([]string{"price"}))
+		if got != w {
+			t.Errorf("got %s, want %s", got, w)
+		}
+	}
+}
+
+func TestCopyValueRefusesBadCells(t *testing.T) {
+	db := New(nil, new(PostgresDialect))
+
+	cells := []*Cell{
+		{Name: "doc", Type: CellJSON, Data: &SQLJSON{Valid: true, Value: []byte("{bad")}},
+		{Name: "doc", Type: CellJSON, Data: &SQLInt{Valid: true, Value: 1}},
+	}
+
+	for _, c := range cells {
+		v, err := copyValue(db, c)
+		if err == nil {
+			t.Errorf("%T copied as %v", c.Data, v)
+		}
+	}
+
+	v, err := copyValue(db, &Cell{Name: "doc", Type: CellJSON, Data: &SQLJSON{}})
+	if v != nil || err != nil {
+		t.Errorf("NULL copied as %v, err %v", v, err)
+	}
+}
@@ -1,14 +1,14 @@
 package scaffold
 
 import (
-	"time"
-
 	"github.com/tidwall/sjson"
 )
 
 // Row structure containing cells
 type Row struct {
 	Cells map[string]*Cell
+	// json is how the row renders as JSON, from the DB it was made by
+	json JSONOptions
 }
 
 // Rows structure that contains an array of rows and the column names
@@ -17,116 +17,25 @@ type Rows struct {
 	Cols []string
 }
 
-// AsJSON gets row data as json bytes
+// AsJSON gets row data as json bytes, cells render as their registered
+// type describes, cells without a value are left out
 func (r *Row) AsJSON(cols []string) []byte {
 	jsv := []byte("{}")
 
 	for _, col := range cols {
 		cell, ok := r.Cells[col]
+		if !ok {
+			continue
+		}
 
-		if ok {
-			switch cell.Type {
-			case CellBool, CellString, CellInt, CellFloat:
-				v, err := cell.GetValue()
-				if err == nil {
-					jsv, _ = sjson.SetBytes(jsv, cell.Name, v)
-				}
-			case CellBytes:
-				v, err := cell.Bytes()
-				if err == nil {
-					jsv, _ = sjson.SetRawBytes(jsv, cell.Name, v)
-				}
-			case CellDate:
-				v, err := cell.Date()
-				if err == nil {
-					jsv, _ = sjson.SetBytes(jsv, cell.Name, v.Format("2006-01-02"))
-				}
-			case CellDatetime:
-				v, err := cell.Date()
-				if err == nil {
-					jsv, _ = sjson.SetBytes(jsv, cell.Name, v.Format(time.RFC3339))
-				}
-			case CellBoolArray:
-				v, err := cell.BoolArray()
-				if err == nil {
-					cjsv := []byte("[]")
-
-					if v != nil {
-						for _, vv := range v {
-							cjsv, _ = sjson.SetBytes(cjsv, "-1", vv)
-						}
-					}
-
-					jsv, _ = sjson.SetRawBytes(jsv, cell.Name, cjsv)
-				}
-			case CellStringArray:
-				v, err := cell.StringArray()
-				if err == nil {
-					cjsv := []byte("[]")
-
-					if v != nil {
-						for _, vv := range v {
-							cjsv, _ = sjson.SetBytes(cjsv, "-1", vv)
-						}
-					}
-
-					jsv, _ = sjson.SetRawBytes(jsv, cell.Name, cjsv)
-				}
-			case CellIntArray:
-				v, err := cell.IntArray()
-				if err == nil {
-					cjsv := []byte("[]")
-
-					if v != nil {
-						for _, vv := range v {
-							cjsv, _ = sjson.SetBytes(cjsv, "-1", vv)
-						}
-					}
-
-					jsv, _ = sjson.SetRawBytes(jsv, cell.Name, cjsv)
-
-				}
-			case CellFloatArray:
-				v, err := cell.FloatArray()
-				if err == nil {
-					cjsv := []byte("[]")
-
-					if v != nil {
-						for _, vv := range v {
-							cjsv, _ = sjson.SetBytes(cjsv, "-1", vv)
-						}
-					}
-
-					jsv, _ = sjson.SetRawBytes(jsv, cell.Name, cjsv)
-				}
-			case CellDateArray:
-				v, err := cell.DateArray()
-				if err == nil {
-					cjsv := []byte("[]")
-
-					if v != nil {
-						for _, vv := range v {
-							cjsv, _ = sjson.SetBytes(cjsv, "-1", vv)
-						}
-					}
-
-					jsv, _ = sjson.SetRawBytes(jsv, cell.Name, cjsv)
-				}
-			case CellDatetimeArray:
-				v, err := cell.DatetimeArray()
-				if err == nil {
-					cjsv := []byte("[]")
-
-					if v != nil {
-						for _, vv := range v {
-							cjsv, _ = sjson.SetBytes(cjsv, "-1", vv)
-						}
-					}
-
-					jsv, _ = sjson.SetRawBytes(jsv, cell.Name, cjsv)
-				}
-			}
+		v, err := cell.GetValue()
+		if err != nil {
+			continue
+		}
 
+		raw, err := cellJSON(cell.Type, v, r.json)
+		if err == nil {
+			jsv, _ = sjson.SetRawBytes(jsv, cell.Name, raw)
 		}
 	}
 
@@ -151,15 +60,24 @@ func (r *Rows) AsJSON() ([]byte, error) {
 	return jsv, nil
 }
 
-// MarshalJSON to more flexibly deal with variant data
+// MarshalJSON to more flexibly deal with variant data, cells render as
+// they do for AsJSON
 func (r *Row) MarshalJSON() ([]byte, error) {
 	b := []byte("{}")
 
 	for _, cell := range r.Cells {
 		v, err := cell.GetValue()
-		if err == nil {
-			b, _ = sjson.SetBytes(b, cell.Name, v)
-		} else {
+		if err != nil {
+			return b, err
+		}
+
+		raw, err := cellJSON(cell.Type, v, r.json)
+		if err != nil {
+			return b, err
+		}
+
+		b, err = sjson.SetRawBytes(b, cell.Name, raw)
+		if err != nil {
 			return b, err
 		}
 	}
@@ -0,0 +1,39 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRowMarshalJSONMatchesAsJSON(t *testing.T) {
+	row := &Row{Cells: map[string]*Cell{
+		"doc":  {Name: "doc", Type: CellJSON},
+		"name": {Name: "name", Type: CellString},
+	}}
+
+	err := row.Cells["doc"].SetJSON([]byte(`{"a":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	row.Cells["name"].SetString("x")
+
+	b, err := json.Marshal(row)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"doc":{"a":1},"name":"x"}`
+
+	var got, asJSON interface{}
+
+	json.Unmarshal(b, &got)
+	json.Unmarshal(row.AsJSON([]string{"doc", "name"}), &asJSON)
+
+	for _, v := range []interface{}{got, asJSON} {
+		out, _ := json.Marshal(v)
+		if string(out) != want {
+			t.Errorf("got %s, want %s", out, want)
+		}
+	}
+}
@@ -1,17 +1,22 @@
 package scaffold
 
 import (
-	"bytes"
+	"context"
 	"database/sql"
-	"errors"
-	"log"
+	"io"
 	"text/template"
+	"time"
 )
 
-var db *sql.DB
+// tmpl holds the parsed templates, they are read only once parsed and
+// shared by every DB
 var tmpl *template.Template
-var tables map[string]*Table
-var mode string
+
+// tmplErr is the failure to parse the templates, returned by every render
+var tmplErr error
+
+// std is the DB used by the package level functions
+var std = New(nil, nil)
 
 // NewTable generates a table
 func NewTable(name string, cells []*Cell) *Table {
@@ -20,226 +25,165 @@ func NewTable(name string, cells []*Cell) *Table {
 	t.Name = name
 	t.Cells = cells
 
-	tables[name] = t
+	std.mu.Lock()
+	std.tables[name] = t
+	std.mu.Unlock()
 
 	return t
 }
 
 // GetTable gets table def
 func GetTable(name string) *Table {
-	val, found := tables[name]
-	if found {
-		return val
-	}
-
-	return nil
+	return std.GetTable(name)
 }
 
 // Raw runs a raw query
-func Raw(q string) {
-	db.Exec(q)
-	return
+func Raw(q string) error {
+	return std.Raw(q)
+}
+
+// RawContext works as Raw, the context cancels the query
+func RawContext(ctx context.Context, q string) error {
+	return std.RawContext(ctx, q)
 }
 
 // GetRaw runs a raw query that expects results
 func GetRaw(q string) (*Rows, error) {
-	result := new(Rows)
-
-	result.Rows = make([]*Row, 0)
-	result.Cols = make([]string, 0)
-
-	rows, err := db.Query(q)
-	if err != nil {
-		return result, errors.New("Failure to execute query")
-	}
-	defer rows.Close()
+	return std.GetRaw(q)
+}
 
-	cols, err := rows.ColumnTypes()
-	if err != nil {
-		return result, errors.New("Failure to extract column types")
-	}
+// GetRawContext works as GetRaw, the context cancels the query
+func GetRawContext(ctx context.Context, q string) (*Rows, error) {
+	return std.GetRawContext(ctx, q)
+}
 
-	for _, v := range cols {
-		result.Cols = append(result.Cols, v.Name())
-	}
+// Exec runs a statement that returns no rows
+func Exec(q string, args ...interface{}) (Result, error) {
+	return std.Exec(q, args...)
+}
 
-	for rows.Next() {
-		row := new(Row)
-		row.Cells = make(map[string]*Cell, 0)
-
-		scanList := make([]interface{}, 0)
-
-		for _, c := range cols {
-			cell := new(Cell)
-			row.Cells[c.Name()] = cell
-
-			cell.Name = c.Name()
-
-			switch c.DatabaseTypeName() {
-			case "BOOL", "BIT":
-				data := NewSQLBool()
-				cell.Data = data
-				cell.Type = CellBool
-				scanList = append(scanList, cell.CellTarget())
-			case "BOOL[]", "BIT[]":
-				xx := NewSQLBoolArray()
-
-				cell.Data = xx
-				scanList = append(scanList, cell.CellTarget())
-			case "TEXT", "VARCHAR", "NVARCHAR":
-				data := NewSQLString()
-				cell.Data = data
-				cell.Type = CellString
-				scanList = append(scanList, cell.CellTarget())
-			case "TEXT[]", "VARCHAR[]", "NVARCHAR[]":
-				xx := NewSQLStringArray()
-
-				cell.Data = xx
-				scanList = append(scanList, cell.CellTarget())
-			case "INT", "INT4", "INT8", "BIGINT", "SMALLINT":
-				data := NewSQLInt()
-				cell.Data = data
-				cell.Type = CellInt
-				scanList = append(scanList, cell.CellTarget())
-			case "INT[]", "INT4[]", "INT8[]", "BIGINT[]", "SMALLINT[]":
-				xx := NewSQLIntArray()
-
-				cell.Data = xx
-				scanList = append(scanList, cell.CellTarget())
-			case "FLOAT", "FLOAT4", "FLOAT8", "DECIMAL", "MONEY", "NUMERIC":
-				data := NewSQLFloat()
-				cell.Data = data
-				cell.Type = CellFloat
-				scanList = append(scanList, cell.CellTarget())
-			case "FLOAT[]", "FLOAT4[]", "FLOAT8[]", "DECIMAL[]", "MONEY[]", "NUMERIC[]":
-				xx := NewSQLFloatArray()
-
-				cell.Data = xx
-				scanList = append(scanList, cell.CellTarget())
-			case "DATE":
-				data := NewSQLDate()
-				cell.Data = data
-				cell.Type = CellDate
-				scanList = append(scanList, cell.CellTarget())
-			case "DATE[]":
-				xx := NewSQLDateArray()
-
-				cell.Data = xx
-				scanList = append(scanList, cell.CellTarget())
-			case "DATETIME", "SMALLDATETIME":
-				data := NewSQLDatetime()
-				cell.Data = data
-				cell.Type = CellDatetime
-				scanList = append(scanList, cell.CellTarget())
-			case "DATETIME[]", "SMALLDATETIME[]":
-				xx := NewSQLDatetimeArray()
-
-				cell.Data = xx
-				scanList = append(scanList, cell.CellTarget())
-			case "JSONB":
-				data := NewSQLBytes()
-				cell.Data = data
-				cell.Type = CellBytes
-				scanList = append(scanList, cell.CellTarget())
-			default:
-				panic("field type not accounted for: " + c.DatabaseTypeName())
-			}
-		}
+// ExecContext works as Exec, the context cancels the query
+func ExecContext(ctx context.Context, q string, args ...interface{}) (Result, error) {
+	return std.ExecContext(ctx, q, args...)
+}
 
-		err := rows.Scan(scanList...)
-		if err != nil {
-			return result, errors.New("Failure to scan row")
-		}
-		result.Rows = append(result.Rows, row)
-	}
+// QueryRaw runs a query and reads every row, it is DB.Query on the package
+// level DB, the name Query is taken by the query type
+func QueryRaw(q string, args ...interface{}) (*Rows, error) {
+	return std.Query(q, args...)
+}
 
-	return result, nil
+// QueryRawContext works as QueryRaw, the context cancels the query
+func QueryRawContext(ctx context.Context, q string, args ...interface{}) (*Rows, error) {
+	return std.QueryContext(ctx, q, args...)
 }
 
 func init() {
-	tables = make(map[string]*Table)
-
-	var err error
-
-	tmpl = new(template.Template)
+	tmpl, tmplErr = parseTemplates()
+}
 
+// parseTemplates parses every SQL template
+func parseTemplates() (*template.Template, error) {
 	funcMap := template.FuncMap{
 		"inc": func(i int) int {
 			return i + 1
 		},
+		// keyword writes the whitelisted spelling of an operator or direction
+		"keyword": normalizeKeyword,
+		"node": func(stmt *statement, filters []Filter) map[string]interface{} {
+			return map[string]interface{}{"stmt": stmt, "filters": filters}
+		},
 	}
 
-	tmpl, err = tmpl.New("filter").Funcs(funcMap).Parse(filterTemplate)
-	if err != nil {
-		log.Fatal(err)
+	templates := []struct {
+		name string
+		text string
+	}{
+		{"filter", filterTemplate},
+		{"query", queryTemplate},
+		{"schema", schemaTemplate},
+		{"insert", insertTemplate},
+		{"select", selectTemplate},
+		{"returning", returningTemplate},
+		{"update", updateTemplate},
+		{"delete", deleteTemplate},
 	}
 
-	tmpl, err = tmpl.New("query").Funcs(funcMap).Parse(queryTemplate)
-	if err != nil {
-		log.Fatal(err)
-	}
+	t := new(template.Template)
 
-	tmpl, err = tmpl.New("schema").Funcs(funcMap).Parse(schemaTemplate)
-	if err != nil {
-		log.Fatal(err)
-	}
+	for _, v := range templates {
+		var err error
 
-	tmpl, err = tmpl.New("insert").Funcs(funcMap).Parse(insertTemplate)
-	if err != nil {
-		log.Fatal(err)
+		t, err = t.New(v.name).Funcs(funcMap).Parse(v.text)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	tmpl, err = tmpl.New("select").Funcs(funcMap).Parse(selectTemplate)
-	if err != nil {
-		log.Fatal(err)
-	}
+	return t, nil
 }
 
-func CreateTable(t *Table) error {
-	var b bytes.Buffer
-
-	err := tmpl.ExecuteTemplate(&b, "schema", t)
-	if err != nil {
-		return err
+// render executes a named template, failing when the templates did not
+// parse
+func render(w io.Writer, name string, data interface{}) error {
+	if tmplErr != nil {
+		return tmplErr
 	}
 
-	_, err = db.Exec(b.String())
-	if err != nil {
-		return err
-	}
+	return tmpl.ExecuteTemplate(w, name, data)
+}
 
-	return nil
+// CreateTable creates the table if it does not exist yet
+func CreateTable(t *Table) error {
+	return t.client().CreateTable(t)
 }
 
+// CreateTableContext works as CreateTable, the context cancels the query
+func CreateTableContext(ctx context.Context, t *Table) error {
+	return t.client().CreateTableContext(ctx, t)
+}
+
+// GetTrue returns the true literal for the dialect
 func GetTrue() string {
-	switch mode {
-	case "postgres":
-		return "TRUE"
-	case "sqlite":
-		return "1"
-	}
-	return "TRUE"
+	return std.True()
 }
 
+// GetFalse returns the false literal for the dialect
 func GetFalse() string {
-	switch mode {
-	case "postgres":
-		return "FALSE"
-	case "sqlite":
-		return "0"
-	}
-	return "TRUE"
+	return std.False()
 }
 
-// Bootstrap connects the db
-func Bootstrap(_db *sql.DB, tables []*Table, _mode string) {
-	db = _db
-	mode = _mode
+// Bootstrap connects the db and creates every table, stopping at the
+// first failure
+func Bootstrap(_db *sql.DB, tables []*Table, _mode string) error {
+	std.conn = _db
+	std.exec = _db
+	std.dialect = DialectFor(_mode)
 
 	for _, table := range tables {
 		err := CreateTable(table)
 		if err != nil {
-			log.Fatal("couldn't create database table")
+			return err
 		}
 	}
+
+	return nil
+}
+
+// SetStrictTypes makes GetRaw fail with ErrUnknownType on unknown column
+// types, by default they are read as text
+func SetStrictTypes(strict bool) {
+	std.SetStrictTypes(strict)
+}
+
+// SetDecimalJSONNumbers makes Row.AsJSON write decimals as JSON numbers
+// for rows read through the package level DB
+func SetDecimalJSONNumbers(on bool) {
+	std.SetDecimalJSONNumbers(on)
+}
+
+// SetTimeLocation sets the location datetimes are read and written in by
+// the package level DB
+func SetTimeLocation(loc *time.Location) {
+	std.SetTimeLocation(loc)
 }
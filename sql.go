@@ -1,23 +1,21 @@
 package scaffold
 
 const filterTemplate = `
-{{- range $index, $filter := .query.Filters }}
-{{if $index}}{{if eq $filter.Operator ""}}AND{{else}}{{$filter.Operator}}{{end}}{{else}}WHERE{{end}}
-{{- if not $filter.Group}}
-"{{$filter.Field}}" {{$filter.Comparison}} {{$filter.Value}}
-{{- else}}
+{{- define "group" -}}
+{{- range $index, $filter := .filters -}}
+{{- if $index}}
+{{with keyword $filter.Operator}}{{.}}{{else}}AND{{end}} {{end -}}
+{{- if $filter.Group -}}
 (
-	{{ range $indexInner, $filterInner := $filter.Group -}}
-		{{if $indexInner}}{{if eq $filterInner.Operator ""}}
-	AND
-	{{else}}
-	{{$filterInner.Operator}}
-	{{end}}{{end -}}
-		"{{$filterInner.Field}}" {{$filterInner.Comparison}} {{$filterInner.Value}}
-	{{- end }}
+{{template "group" (node $.stmt $filter.Group)}}
 )
+{{- else -}}
+{{$.stmt.Compare $filter}}
+{{- end -}}
 {{- end -}}
 {{- end }}
+WHERE
+{{template "group" (node .stmt .query.Filters)}}
 `
 
 const queryTemplate = `
@@ -28,45 +26,82 @@ const queryTemplate = `
 	{{- if .query.Orders -}}
 		{{- range $index, $order := .query.Orders -}}
 		{{- if $index}},{{else}}
-ORDER BY{{end}} "{{$order.Field}}" {{$order.Direction}}
+ORDER BY{{end}} {{$.stmt.OrderBy $order}}
 		{{- end -}}
 	{{- end }}
-{{ if ge .query.Limit 0 }}LIMIT {{.query.Limit}}{{ end }}
-{{ if ge .query.Offset 0 }}OFFSET {{.query.Offset}}{{ end }}
+{{ .stmt.LimitOffset .query.Limit .query.Offset }}
 {{- end -}}
 `
 
 const schemaTemplate = `
-CREATE TABLE IF NOT EXISTS {{.Name}} (
-	{{ range $index, $cell := .Cells -}}
+CREATE TABLE IF NOT EXISTS {{.stmt.Quote .table.Name}} (
+	{{ range $index, $cell := .table.Cells -}}
 		{{if $index}},{{end -}}
-		"{{$cell.Name}}" {{$cell.SQL}}
+		{{$.stmt.Quote $cell.Name}} {{$.stmt.ColumnType $cell}}
+	{{end}}
+	{{- if .keys}},PRIMARY KEY ({{range $index, $key := .keys}}{{if $index}}, {{end}}{{$.stmt.Quote $key}}{{end}})
 	{{end}}
 )
 `
 
 const insertTemplate = `
-INSERT INTO {{.table.Name}} (
+INSERT INTO {{.stmt.Quote .table.Name}}
+{{- if .fields}} (
 	{{ range $index, $field := .fields -}}
 		{{if $index}},{{end -}}
-		"{{$field}}"
+		{{$.stmt.Quote $field}}
 	{{end}}
 )
-values (
-	{{ range $index, $ph := .placeholders -}}
+values
+{{- range $rowIndex, $placeholders := .values}}{{if $rowIndex}},{{end}} (
+	{{ range $index, $ph := $placeholders -}}
 		{{- if $index}},{{end -}}
 		{{$ph}}
 	{{end}}
 )
-{{- if ne .returning ""}}
-RETURNING id
-{{ end -}}
+{{- end}}
+{{- else}}
+{{.stmt.DefaultValues}}
+{{- end}}
+{{- if .upsert}}
+{{.stmt.Upsert .conflict .updates .fields}}
+{{- end}}
+{{- template "returning" . -}}
 `
 const selectTemplate = `
 SELECT
 	{{ range $index, $field := .fields -}}
-		{{if $index}},{{end}}"{{$field}}"
+		{{if $index}},{{end}}{{$.stmt.Quote $field}}
 	{{end}}
-FROM "{{.table.Name}}"
+FROM {{.stmt.Quote .table.Name}}
 {{- template "query" . -}}
 `
+
+const updateTemplate = `
+UPDATE {{.stmt.Quote .table.Name}} SET
+	{{ range $index, $field := .fields -}}
+		{{if $index}},{{end -}}
+		{{$.stmt.Quote $field}} = {{index $.placeholders $index}}
+	{{end}}
+{{- if .query.Filters -}}
+{{- template "filter" . -}}
+{{- end -}}
+{{- template "returning" . -}}
+`
+
+const deleteTemplate = `
+DELETE FROM {{.stmt.Quote .table.Name}}
+{{- if .query.Filters -}}
+{{- template "filter" . -}}
+{{- end -}}
+{{- template "returning" . -}}
+`
+
+const returningTemplate = `
+{{- if .returning}}
+RETURNING
+	{{ range $index, $field := .returning -}}
+		{{if $index}},{{end}}{{$.stmt.Quote $field}}
+	{{end}}
+{{- end -}}
+`
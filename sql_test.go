@@ -0,0 +1,390 @@
+package scaffold
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// squash collapses the whitespace of rendered SQL so it compares on one line
+func squash(q string) string {
+	return strings.Join(strings.Fields(q), " ")
+}
+
+// testTable is bound to a DB without a connection for rendering
+func testTable(d Dialect) *Table {
+	return New(nil, d).NewTable("users", []*Cell{
+		{Name: "email", Type: CellString, PrimaryKey: true},
+		{Name: "name", Type: CellString},
+		{Name: "tags", Type: CellStringArray},
+		{Name: "age", Type: CellInt},
+	})
+}
+
+func TestRenderCreateTable(t *testing.T) {
+	cases := []struct {
+		dialect Dialect
+		want    string
+	}{
+		{new(PostgresDialect), `CREATE TABLE IF NOT EXISTS "users" ( "email" TEXT ,"name" TEXT ,"tags" TEXT[] ,"age" BIGINT ,PRIMARY KEY ("email") )`},
+		{new(SQLiteDialect), `CREATE TABLE IF NOT EXISTS "users" ( "email" TEXT ,"name" TEXT ,"tags" TEXT ,"age" INTEGER ,PRIMARY KEY ("email") )`},
+		{new(MySQLDialect), "CREATE TABLE IF NOT EXISTS `users` ( `email` VARCHAR(255) ,`name` TEXT ,`tags` JSON ,`age` BIGINT ,PRIMARY KEY (`email`) )"},
+	}
+
+	for _, c := range cases {
+		tb := testTable(c.dialect)
+
+		q, err := tb.client().renderCreateTable(tb)
+		if err != nil {
+			t.Fatalf("%s: %v", c.dialect.Name(), err)
+		}
+
+		if squash(q) != c.want {
+			t.Errorf("%s:\n got %s\nwant %s", c.dialect.Name(), squash(q), c.want)
+		}
+	}
+}
+
+func TestRenderSelect(t *testing.T) {
+	q := Query{
+		Filters: []Filter{
+			{Field: "age", Comparison: Gte, Value: 18},
+			{Operator: " or", Field: "name", Comparison: In, Value: []string{"a", "b"}},
+		},
+		Orders: []Order{{Field: "age", Direction: "desc "}},
+		Limit:  10,
+		Offset: -1,
+	}
+
+	cases := []struct {
+		dialect Dialect
+		want    string
+	}{
+		{new(PostgresDialect), `SELECT "email" ,"name" ,"tags" ,"age" FROM "users" WHERE "age" >= $1 OR "name" IN ($2, $3) ORDER BY "age" DESC LIMIT 10`},
+		{new(SQLiteDialect), `SELECT "email" ,"name" ,"tags" ,"age" FROM "users" WHERE "age" >= ? OR "name" IN (?, ?) ORDER BY "age" DESC LIMIT 10`},
+		{new(MySQLDialect), "SELECT `email` ,`name` ,`tags` ,`age` FROM `users` WHERE `age` >= ? OR `name` IN (?, ?) ORDER BY `age` DESC LIMIT 10"},
+	}
+
+	for _, c := range cases {
+		b, st, err := testTable(c.dialect).renderSelect(q)
+		if err != nil {
+			t.Fatalf("%s: %v", c.dialect.Name(), err)
+		}
+
+		if squash(b) != c.want {
+			t.Errorf("%s:\n got %s\nwant %s", c.dialect.Name(), squash(b), c.want)
+		}
+
+		want := []interface{}{int64(18), "a", "b"}
+		if !reflect.DeepEqual(st.Args(), want) {
+			t.Errorf("%s: args %v, want %v", c.dialect.Name(), st.Args(), want)
+		}
+	}
+}
+
+func TestRenderUpsert(t *testing.T) {
+	cases := []struct {
+		dialect Dialect
+		want    string
+		tags    interface{}
+	}{
+		{new(PostgresDialect), `INSERT INTO "users" ( "email" ,"tags" ) values ( $1 ,ARRAY[$2,$3]::text[] ) ON CONFLICT ("email") DO UPDATE SET "tags" = EXCLUDED."tags"`, "x"},
+		{new(SQLiteDialect), `INSERT INTO "users" ( "email" ,"tags" ) values ( ? ,? ) ON CONFLICT ("email") DO UPDATE SET "tags" = EXCLUDED."tags"`, `["x","y"]`},
+		{new(MySQLDialect), "INSERT INTO `users` ( `email` ,`tags` ) values ( ? ,? ) ON DUPLICATE KEY UPDATE `tags` = VALUES(`tags`)", `["x","y"]`},
+	}
+
+	for _, c := range cases {
+		tb := testTable(c.dialect)
+
+		row := tb.NewRow()
+		row.Cells["email"].SetString("a@b")
+		row.Cells["tags"].Data = &SQLStringArray{Valid: true, Value: []string{"x", "y"}}
+
+		st := tb.client().newStatement()
+
+		vars, err := tb.insertVars(row, st)
+		if err != nil {
+			t.Fatalf("%s: %v", c.dialect.Name(), err)
+		}
+
+		vars["upsert"] = true
+		vars["conflict"] = []string{"email"}
+		vars["updates"] = []string{"tags"}
+
+		var b strings.Builder
+
+		err = render(&b, "insert", vars)
+		if err != nil {
+			t.Fatalf("%s: %v", c.dialect.Name(), err)
+		}
+
+		if squash(b.String()) != c.want {
+			t.Errorf("%s:\n got %s\nwant %s", c.dialect.Name(), squash(b.String()), c.want)
+		}
+
+		if st.Args()[1] != c.tags {
+			t.Errorf("%s: tags bound as %v, want %v", c.dialect.Name(), st.Args()[1], c.tags)
+		}
+	}
+}
+
+func TestRenderInsertDefaults(t *testing.T) {
+	cases := []struct {
+		dialect Dialect
+		want    string
+	}{
+		{new(PostgresDialect), `INSERT INTO "items" DEFAULT VALUES`},
+		{new(SQLiteDialect), `INSERT INTO "items" DEFAULT VALUES`},
+		{new(MySQLDialect), "INSERT INTO `items` () VALUES ()"},
+	}
+
+	for _, c := range cases {
+		tb := New(nil, c.dialect).NewTable("items", []*Cell{
+			{Name: "id", Type: CellInt, PrimaryKey: true},
+		})
+
+		vars, err := tb.insertVars(tb.NewRow(), tb.client().newStatement())
+		if err != nil {
+			t.Fatalf("%s: %v", c.dialect.Name(), err)
+		}
+
+		var b strings.Builder
+
+		err = render(&b, "insert", vars)
+		if err != nil {
+			t.Fatalf("%s: %v", c.dialect.Name(), err)
+		}
+
+		if squash(b.String()) != c.want {
+			t.Errorf("%s:\n got %s\nwant %s", c.dialect.Name(), squash(b.String()), c.want)
+		}
+	}
+}
+
+func TestRenderInsertGeneratesKeys(t *testing.T) {
+	tb := New(nil, new(PostgresDialect)).NewTable("docs", []*Cell{
+		{Name: "id", Type: CellUUID, PrimaryKey: true, Generate: NewUUIDv7},
+		{Name: "title", Type: CellString},
+	})
+
+	row := tb.NewRow()
+	row.Cells["title"].SetString("a")
+
+	st := tb.client().newStatement()
+
+	vars, err := tb.insertVars(row, st)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+
+	err = render(&b, "insert", vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `INSERT INTO "docs" ( "id" ,"title" ) values ( $1 ,$2 )`
+	if squash(b.String()) != want {
+		t.Errorf("\n got %s\nwant %s", squash(b.String()), want)
+	}
+
+	id, err := row.Cells["id"].UUID()
+	if err != nil || id.IsZero() || st.Args()[0] != id {
+		t.Errorf("generated key %s bound as %v, err %v", id, st.Args()[0], err)
+	}
+
+	row = tb.NewRow()
+	row.Cells["id"].SetUUID(id)
+
+	_, err = tb.insertVars(row, tb.client().newStatement())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kept, _ := row.Cells["id"].UUID()
+	if kept != id {
+		t.Errorf("set key replaced by %s", kept)
+	}
+}
+
+func TestRenderDelete(t *testing.T) {
+	tb := testTable(new(PostgresDialect))
+
+	b, st, err := tb.renderDelete(Query{Filters: []Filter{{Field: "email", Comparison: Eq, Value: "a@b"}}}, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `DELETE FROM "users" WHERE "email" = $1`
+	if squash(b) != want || len(st.Args()) != 1 {
+		t.Errorf("got %s %v, want %s", squash(b), st.Args(), want)
+	}
+
+	_, _, err = tb.renderDelete(Query{}, false, false)
+
+	var v *ValidationError
+	if !errors.As(err, &v) {
+		t.Errorf("delete without filters rendered, err %v", err)
+	}
+
+	b, _, err = tb.renderDelete(Query{}, false, true)
+	if err != nil || squash(b) != `DELETE FROM "users"` {
+		t.Errorf("delete all rendered %s, err %v", squash(b), err)
+	}
+}
+
+func TestRenderRejectsInvalidQueries(t *testing.T) {
+	tb := testTable(new(PostgresDialect))
+
+	queries := []Query{
+		{Filters: []Filter{{Field: "missing", Comparison: Eq, Value: 1}}},
+		{Filters: []Filter{{Operator: "; DROP", Field: "age", Comparison: Eq, Value: 1}}},
+		{Orders: []Order{{Field: "age", Direction: "DESC; DROP"}}},
+		{Filters: []Filter{{Field: "name", Path: "a.b", Comparison: Eq, Value: "x"}}},
+		{Filters: []Filter{{Field: "name", Comparison: JSONContains, Value: `{"a":1}`}}},
+		{Filters: []Filter{{Field: "age", Comparison: Eq, Value: uint64(1)}}},
+		{Filters: []Filter{{Field: "age", Comparison: In, Value: []interface{}{1, nil}}}},
+		{Filters: []Filter{{Field: "age", Comparison: Between, Value: []uint64{1, 2}}}},
+	}
+
+	for _, q := range queries {
+		_, _, err := tb.renderSelect(q)
+
+		var v *ValidationError
+		if !errors.As(err, &v) {
+			t.Errorf("%+v rendered, err %v", q, err)
+		}
+	}
+}
+
+func TestRenderRefusesUnorderedDecimals(t *testing.T) {
+	cells := []*Cell{{Name: "price", Type: CellDecimal}}
+
+	queries := []struct {
+		q  Query
+		ok bool
+	}{
+		{Query{Filters: []Filter{{Field: "price", Comparison: Eq, Value: "10.05"}}}, true},
+		{Query{Filters: []Filter{{Field: "price", Comparison: Gt, Value: "9"}}}, false},
+		{Query{Filters: []Filter{{Field: "price", Comparison: Between, Value: []string{"1", "9"}}}}, false},
+		{Query{Orders: []Order{{Field: "price"}}}, false},
+	}
+
+	for _, d := range []Dialect{new(SQLiteDialect), new(PostgresDialect)} {
+		tb := New(nil, d).NewTable("prices", cells)
+
+		for _, c := range queries {
+			_, _, err := tb.renderSelect(c.q)
+
+			var v *ValidationError
+
+			want := c.ok || d.Name() != "sqlite"
+			if want && err != nil || !want && (!errors.As(err, &v) || v.Field != "price") {
+				t.Errorf("%s %+v: err %v", d.Name(), c.q, err)
+			}
+		}
+	}
+}
+
+func TestRenderTimeFiltersFollowTheColumn(t *testing.T) {
+	tb := New(nil, new(SQLiteDialect)).NewTable("events", []*Cell{
+		{Name: "at", Type: CellDatetime},
+		{Name: "tz", Type: CellDatetimeTZ},
+	})
+
+	v := time.Date(2024, 3, 1, 15, 30, 0, 0, time.UTC)
+
+	_, st, err := tb.renderSelect(Query{Filters: []Filter{
+		{Field: "at", Comparison: Eq, Value: v},
+		{Field: "tz", Comparison: Eq, Value: v},
+		{Field: "tz", Comparison: In, Value: []time.Time{v}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []interface{}{"2024-03-01T15:30:00.000000", "2024-03-01T15:30:00.000000Z", "2024-03-01T15:30:00.000000Z"}
+	if !reflect.DeepEqual(st.Args(), want) {
+		t.Errorf("args %v, want %v", st.Args(), want)
+	}
+}
+
+func TestTimeLocationIsPerDB(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	cells := []*Cell{{Name: "at", Type: CellDatetime}}
+	v := time.Date(2024, 3, 1, 15, 30, 0, 0, time.UTC)
+	q := Query{Filters: []Filter{{Field: "at", Comparison: Eq, Value: v}}}
+
+	utc := New(nil, new(SQLiteDialect))
+	local := New(nil, new(SQLiteDialect))
+	local.SetTimeLocation(ny)
+
+	_, st, err := utc.NewTable("events", cells).renderSelect(q)
+	if err != nil || st.Args()[0] != "2024-03-01T15:30:00.000000" {
+		t.Errorf("utc bound %v, err %v", st.Args(), err)
+	}
+
+	_, st, err = local.NewTable("events", cells).renderSelect(q)
+	if err != nil || st.Args()[0] != "2024-03-01T10:30:00.000000" {
+		t.Errorf("new york bound %v, err %v", st.Args(), err)
+	}
+
+	data := local.cellData(CellDatetime).(*SQLDatetime)
+
+	err = data.Scan("2024-03-01T10:30:00.000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := data.Value
+	if !got.Equal(v) || got.Location() != ny {
+		t.Errorf("new york scanned %v", got)
+	}
+}
+
+func TestZonedTimesSortAcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	db := New(nil, new(SQLiteDialect))
+	db.SetTimeLocation(ny)
+
+	tb := db.NewTable("events", []*Cell{{Name: "tz", Type: CellDatetimeTZ}})
+
+	// 01:30 EDT comes before 01:10 EST on the night the clocks go back
+	early := time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC)
+	late := time.Date(2026, 11, 1, 6, 10, 0, 0, time.UTC)
+
+	_, st, err := tb.renderSelect(Query{Filters: []Filter{
+		{Field: "tz", Comparison: Between, Value: []time.Time{early, late}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lo, _ := st.Args()[0].(string)
+	hi, _ := st.Args()[1].(string)
+	if lo >= hi {
+		t.Errorf("%s sorts after %s", lo, hi)
+	}
+
+	data := db.cellData(CellDatetimeTZ).(*SQLDatetimeTZ)
+
+	err = data.Scan(hi)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !data.Value.Equal(late) || data.Value.Location() != ny {
+		t.Errorf("%s scanned as %v", hi, data.Value)
+	}
+}
@@ -0,0 +1,250 @@
+package scaffold
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// statement collects the arguments bound while rendering a template
+type statement struct {
+	dialect Dialect
+	args    []interface{}
+	// table the filters refer to, nil for queries rendered on their own
+	table *Table
+	// loc is the time location of the DB, datetimes are bound in it
+	loc *time.Location
+}
+
+// newStatement makes a statement for a dialect
+func newStatement(d Dialect) *statement {
+	s := new(statement)
+	s.dialect = d
+	s.args = make([]interface{}, 0)
+	s.loc = time.UTC
+
+	return s
+}
+
+// Args bound so far, in placeholder order
+func (s *statement) Args() []interface{} {
+	return s.args
+}
+
+// Bind adds a value to the argument list and returns its placeholder,
+// slices are expanded into a parenthesised list for IN
+func (s *statement) Bind(v interface{}) (string, error) {
+	return s.bindField(v, false)
+}
+
+// bindField works as Bind, datetimes are bound for a column with a time
+// zone when zoned is set
+func (s *statement) bindField(v interface{}, zoned bool) (string, error) {
+	list, ok := listValues(v)
+	if !ok {
+		return s.bindOne(v, zoned)
+	}
+
+	if len(list) == 0 {
+		return "", errors.New("cannot bind an empty list")
+	}
+
+	placeholders := make([]string, 0, len(list))
+
+	for _, item := range list {
+		p, err := s.bindOne(item, zoned)
+		if err != nil {
+			return "", err
+		}
+
+		placeholders = append(placeholders, p)
+	}
+
+	return "(" + strings.Join(placeholders, ", ") + ")", nil
+}
+
+// bindOne adds a single scalar value to the argument list, datetimes are
+// bound as for a column with or without a time zone
+func (s *statement) bindOne(v interface{}, zoned bool) (string, error) {
+	value, err := bindValue(v)
+	if err != nil {
+		return "", err
+	}
+
+	t, ok := value.(time.Time)
+	if ok {
+		value = s.dialect.BindTime(t, zoned, s.loc)
+	}
+
+	return s.bindRaw(value), nil
+}
+
+// zoned reports whether a filter compares a datetime cell of the table
+// that keeps its time zone
+func (s *statement) zoned(f Filter) bool {
+	if s.table == nil || f.Path != "" {
+		return false
+	}
+
+	t, ok := s.table.cellType(f.Field)
+	if !ok {
+		return false
+	}
+
+	zoned, _ := zonedTimeCell(t)
+	return zoned
+}
+
+// Quote an identifier for the dialect
+func (s *statement) Quote(name string) string {
+	return s.dialect.Quote(name)
+}
+
+// OrderBy renders one ORDER BY term with its whitelisted direction
+func (s *statement) OrderBy(o Order) (string, error) {
+	if s.unordered(o.Field) {
+		return "", errors.New("cannot order by " + o.Field + ", its values do not sort in " + s.dialect.Name())
+	}
+
+	term := s.Quote(o.Field)
+
+	dir := normalizeKeyword(o.Direction)
+	if dir != "" {
+		term += " " + dir
+	}
+
+	return term, nil
+}
+
+// unordered reports whether a cell of the table does not compare in value
+// order in the dialect
+func (s *statement) unordered(field string) bool {
+	if s.table == nil {
+		return false
+	}
+
+	t, ok := s.table.cellType(field)
+	return ok && isUnordered(s.dialect.Name(), t)
+}
+
+// LimitOffset renders the limit and offset for the dialect
+func (s *statement) LimitOffset(limit int, offset int) string {
+	return s.dialect.LimitOffset(limit, offset)
+}
+
+// ColumnType is the DDL type of a cell, SQL when set, the sized type when
+// Precision is set, the key type for primary keys or the dialect default
+// for its type
+func (s *statement) ColumnType(c *Cell) string {
+	if c.SQL != "" {
+		return c.SQL
+	}
+
+	sized, ok := sizedColumnType(s.dialect.Name(), c)
+	if ok {
+		return sized
+	}
+
+	key, ok := keyColumnType(s.dialect.Name(), c)
+	if ok {
+		return key
+	}
+
+	return s.dialect.ColumnType(c.Type)
+}
+
+// Upsert renders the upsert clause for the dialect
+func (s *statement) Upsert(conflict []string, updates []string, fields []string) (string, error) {
+	return s.dialect.Upsert(conflict, updates, fields)
+}
+
+// DefaultValues renders the values of an insert that sets no columns
+func (s *statement) DefaultValues() string {
+	return s.dialect.DefaultValues()
+}
+
+// maxArgs is the most arguments the dialect accepts in one statement
+func (s *statement) maxArgs() int {
+	return s.dialect.MaxArgs()
+}
+
+// placeholder for the nth argument in the dialect
+func (s *statement) placeholder(n int) string {
+	return s.dialect.Placeholder(n)
+}
+
+// bindRaw adds a value as is and returns its placeholder
+func (s *statement) bindRaw(v interface{}) string {
+	s.args = append(s.args, v)
+	return s.placeholder(len(s.args))
+}
+
+// bindValue normalises a filter value to a type every driver accepts
+func bindValue(v interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case nil:
+		return nil, errors.New("cannot bind nil, use IS NULL")
+	case int64, float64, bool, string, []byte, time.Time, driver.Valuer:
+		return x, nil
+	case int:
+		return int64(x), nil
+	case int8:
+		return int64(x), nil
+	case int16:
+		return int64(x), nil
+	case int32:
+		return int64(x), nil
+	case uint8:
+		return int64(x), nil
+	case uint16:
+		return int64(x), nil
+	case uint32:
+		return int64(x), nil
+	case float32:
+		return float64(x), nil
+	}
+
+	return nil, errors.New("cannot bind value of type " + reflect.TypeOf(v).String())
+}
+
+// BindCell adds the value held by a cell to the argument list and returns
+// the expression to write in its place, NULL cells bind NULL, unset cells
+// should be left out by the caller and bind NULL when they are not
+func (s *statement) BindCell(c *Cell) (string, error) {
+	value, err := c.GetValue()
+	isNull := err != nil
+
+	if !isNull {
+		value, err = bindCellValue(c.Type, value)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if !isArrayCell(c.Type) {
+		if isNull {
+			value = nullValue(c.Type)
+		}
+
+		zoned, ok := zonedTimeCell(c.Type)
+		t, isTime := value.(time.Time)
+		if ok && isTime {
+			value = s.dialect.BindTime(t, zoned, s.loc)
+		}
+
+		return s.bindRaw(value), nil
+	}
+
+	if isNull {
+		return s.dialect.NullArray(c.Type), nil
+	}
+
+	list, ok := listValues(value)
+	if !ok {
+		return "", errors.New("cell " + c.Name + " does not hold an array")
+	}
+
+	return s.dialect.Array(c.Type, list, s.bindRaw)
+}
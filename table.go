@@ -2,21 +2,43 @@ package scaffold
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"errors"
-	"strconv"
 )
 
 // Table structure
 type Table struct {
 	Name  string
 	Cells []*Cell
+	db    *DB
+}
+
+// client is the DB the table is bound to, the package level DB otherwise
+func (t *Table) client() *DB {
+	if t.db != nil {
+		return t.db
+	}
+
+	return std
+}
+
+// session is the DB a query runs on, the transaction carried by ctx when
+// it was opened from the DB the table is bound to
+func (t *Table) session(ctx context.Context) *DB {
+	return t.client().bound(ctx)
+}
+
+// queryError wraps a driver error with the table and the SQL it ran
+func (t *Table) queryError(op string, q string, err error) error {
+	return t.client().queryError(op, t.Name, q, err)
 }
 
 // NewRow creates a row that conforms to the table definition
 func (t *Table) NewRow() *Row {
 	row := new(Row)
 	row.Cells = make(map[string]*Cell)
+	row.json = t.client().json
 
 	for _, proto := range t.Cells {
 		cell := new(Cell)
@@ -24,6 +46,7 @@ func (t *Table) NewRow() *Row {
 		cell.Name = proto.Name
 		cell.Type = proto.Type
 		cell.SQL = proto.SQL
+		cell.PrimaryKey = proto.PrimaryKey
 		row.Cells[cell.Name] = cell
 	}
 
@@ -32,39 +55,66 @@ func (t *Table) NewRow() *Row {
 
 // GetRows runs a query and returns a rows structure
 func (t *Table) GetRows(q Query) (*Rows, error) {
+	return t.GetRowsContext(context.Background(), q)
+}
+
+// GetRowsContext works as GetRows, the context cancels the query
+func (t *Table) GetRowsContext(ctx context.Context, q Query) (*Rows, error) {
 	result := new(Rows)
-	fields := make([]string, 0)
 
 	result.Rows = make([]*Row, 0)
 	result.Cols = make([]string, 0)
 
-	for _, c := range t.Cells {
-		if !c.Exclude {
-			fields = append(fields, c.Name)
-		}
+	b, st, err := t.renderSelect(q)
+	if err != nil {
+		return result, err
 	}
 
+	rows, err := t.session(ctx).exec.QueryContext(ctx, b, st.Args()...)
+	if err != nil {
+		return result, t.queryError("execute query", b, err)
+	}
+	defer rows.Close()
+
+	return t.scanRows(ctx, b, rows, t.selectFields())
+}
+
+// renderSelect builds the SELECT statement for a query
+func (t *Table) renderSelect(q Query) (string, *statement, error) {
+	err := q.Validate(t)
+	if err != nil {
+		return "", nil, err
+	}
+
+	st := t.client().newStatement()
+	st.table = t
+
 	templateVars := make(map[string]interface{}, 0)
 	templateVars["table"] = t
-	templateVars["fields"] = fields
+	templateVars["fields"] = t.selectFields()
 	templateVars["query"] = q
+	templateVars["stmt"] = st
 
 	var b bytes.Buffer
 
-	err := tmpl.ExecuteTemplate(&b, "select", templateVars)
+	err = render(&b, "select", templateVars)
 	if err != nil {
-		return result, errors.New("Failure to execute template")
+		return "", nil, t.queryError("execute template", "", err)
 	}
 
-	rows, err := db.Query(b.String())
-	if err != nil {
-		return result, errors.New("Failure to execute query")
-	}
-	defer rows.Close()
+	return b.String(), st, nil
+}
+
+// scanRows reads every result row into rows typed by the table definition
+func (t *Table) scanRows(ctx context.Context, q string, rows *sql.Rows, fields []string) (*Rows, error) {
+	result := new(Rows)
+
+	result.Rows = make([]*Row, 0)
+	result.Cols = make([]string, 0)
 
 	cols, err := rows.ColumnTypes()
 	if err != nil {
-		return result, errors.New("Failure to extract column types")
+		return result, t.queryError("extract column types", q, err)
 	}
 
 	for _, v := range cols {
@@ -72,266 +122,532 @@ func (t *Table) GetRows(q Query) (*Rows, error) {
 	}
 
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
 		row := t.NewRow()
 		scanList := make([]interface{}, 0)
 
-		for _, col := range t.Cells {
-			c := row.Cells[col.Name]
-
-			switch c.Type {
-			case CellBool:
-				data := NewSQLBool()
-				c.Data = data
-				scanList = append(scanList, c.CellTarget())
-			case CellString:
-				data := NewSQLString()
-				c.Data = data
-				scanList = append(scanList, c.CellTarget())
-			case CellInt:
-				data := NewSQLInt()
-				c.Data = data
-				scanList = append(scanList, c.CellTarget())
-			case CellFloat:
-				data := NewSQLFloat()
-				c.Data = data
-				scanList = append(scanList, c.CellTarget())
-			case CellDate:
-				data := NewSQLDate()
-				c.Data = data
-				scanList = append(scanList, c.CellTarget())
-			case CellDatetime:
-				data := NewSQLDatetime()
-				c.Data = data
-				scanList = append(scanList, c.CellTarget())
-			case CellBoolArray:
-				xx := NewSQLBoolArray()
-
-				c.Data = xx
-				scanList = append(scanList, c.CellTarget())
-			case CellStringArray:
-				xx := NewSQLStringArray()
-
-				c.Data = xx
-				scanList = append(scanList, c.CellTarget())
-			case CellIntArray:
-				xx := NewSQLIntArray()
-
-				c.Data = xx
-				scanList = append(scanList, c.CellTarget())
-			case CellFloatArray:
-				xx := NewSQLFloatArray()
-
-				c.Data = xx
-				scanList = append(scanList, c.CellTarget())
-			case CellDateArray:
-				xx := NewSQLDateArray()
-
-				c.Data = xx
-				scanList = append(scanList, c.CellTarget())
-			case CellDatetimeArray:
-				xx := NewSQLDatetimeArray()
-
-				c.Data = xx
-				scanList = append(scanList, c.CellTarget())
+		for _, field := range fields {
+			c := row.Cells[field]
+
+			c.Data = t.client().cellData(c.Type)
+			if c.Data != nil {
+				scanList = append(scanList, scanTarget(t.client().dialect, c))
 			}
 		}
 
 		err := rows.Scan(scanList...)
 		if err != nil {
-			return result, errors.New("Failure to scan row")
+			return result, t.queryError("scan row", q, err)
 		}
 		result.Rows = append(result.Rows, row)
 	}
 
+	if err := rows.Err(); err != nil {
+		return result, t.queryError("read rows", q, err)
+	}
+
 	return result, nil
 }
 
-// Insert inserts into a table
-func (t *Table) Insert(row *Row, returning string) (int64, error) {
-	fields := make([]string, 0)
-	placeholders := make([]string, 0)
+// Insert inserts into a table and returns the primary key values of the
+// new row, tables without a declared primary key return the column named
+// by returning instead, nothing is returned when that is empty too
+func (t *Table) Insert(row *Row, returning string) ([]interface{}, error) {
+	return t.InsertContext(context.Background(), row, returning)
+}
 
-	for _, tc := range t.Cells {
-		c, ok := row.Cells[tc.Name]
-		if ok {
-			if !c.Exclude {
-				fields = append(fields, c.Name)
-			}
+// InsertContext works as Insert, the context cancels the query
+func (t *Table) InsertContext(ctx context.Context, row *Row, returning string) ([]interface{}, error) {
+	err := t.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	st := t.client().newStatement()
+
+	templateVars, err := t.insertVars(row, st)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := t.primaryKeys()
+	if len(keys) == 0 && returning != "" {
+		keys = []string{returning}
+	}
+
+	if len(keys) > 0 && !st.dialect.SupportsReturning() {
+		return t.insertLastID(ctx, row, templateVars, keys)
+	}
+
+	templateVars["returning"] = keys
+
+	var b bytes.Buffer
+
+	err = render(&b, "insert", templateVars)
+	if err != nil {
+		return nil, t.queryError("execute template", "", err)
+	}
+
+	if len(keys) == 0 {
+		_, err = t.client().execAffected(ctx, t.Name, b.String(), st)
+		return []interface{}{}, err
+	}
+
+	result := t.NewRow()
+	scanList := make([]interface{}, 0, len(keys))
+
+	for _, key := range keys {
+		c, ok := result.Cells[key]
+		if !ok {
+			c = &Cell{Name: key}
+			result.Cells[key] = c
+		}
+
+		c.Data = t.client().cellData(c.Type)
+		if !ok || c.Data == nil {
+			c.Data = new(anyValue)
+		}
+
+		scanList = append(scanList, scanTarget(t.client().dialect, c))
+	}
+
+	err = t.session(ctx).exec.QueryRowContext(ctx, b.String(), st.Args()...).Scan(scanList...)
+	if err != nil {
+		return nil, t.queryError("execute query", b.String(), err)
+	}
+
+	values := make([]interface{}, 0, len(keys))
+
+	for _, key := range keys {
+		v, _ := result.Cells[key].GetValue()
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+// insertLastID runs an insert on dialects without RETURNING, the key is
+// read from the row when it was set and from the last insert id otherwise
+func (t *Table) insertLastID(ctx context.Context, row *Row, templateVars map[string]interface{}, keys []string) ([]interface{}, error) {
+	var b bytes.Buffer
+
+	err := render(&b, "insert", templateVars)
+	if err != nil {
+		return nil, t.queryError("execute template", "", err)
+	}
+
+	st := templateVars["stmt"].(*statement)
+
+	// checked before running, a failure after the insert would leave a row
+	// the caller does not know about
+	values, err := t.rowKeys(row)
+	known := err == nil && len(values) == len(keys)
+
+	if !known && len(keys) != 1 {
+		return nil, errors.New("Failure to read composite key without RETURNING")
+	}
+
+	res, err := t.session(ctx).exec.ExecContext(ctx, b.String(), st.Args()...)
+	if err != nil {
+		return nil, t.queryError("execute query", b.String(), err)
+	}
+
+	if known {
+		return values, nil
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, t.queryError("read last insert id", b.String(), err)
+	}
+
+	return []interface{}{id}, nil
+}
+
+// InsertReturning inserts into a table and gives back the stored row as
+// the database sees it, including defaults, trigger changes and generated
+// columns, dialects without RETURNING read the row back by its key
+func (t *Table) InsertReturning(row *Row) (*Row, error) {
+	return t.InsertReturningContext(context.Background(), row)
+}
+
+// InsertReturningContext works as InsertReturning, the context cancels the query
+func (t *Table) InsertReturningContext(ctx context.Context, row *Row) (*Row, error) {
+	err := t.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	st := t.client().newStatement()
+
+	if !st.dialect.SupportsReturning() {
+		if len(t.primaryKeys()) == 0 {
+			return nil, errors.New("Failure to read back a row without RETURNING, no primary key declared")
+		}
+
+		keys, err := t.InsertContext(ctx, row, "")
+		if err != nil {
+			return nil, err
 		}
+
+		return t.GetContext(ctx, keys...)
+	}
+
+	templateVars, err := t.insertVars(row, st)
+	if err != nil {
+		return nil, err
+	}
+
+	templateVars["returning"] = t.selectFields()
+
+	var b bytes.Buffer
+
+	err = render(&b, "insert", templateVars)
+	if err != nil {
+		return nil, t.queryError("execute template", "", err)
+	}
+
+	rows, err := t.queryRows(ctx, b.String(), st)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows.Rows) == 0 {
+		return nil, &QueryError{Op: "find row", Table: t.Name, Kind: ErrNotFound}
+	}
+
+	return rows.Rows[0], nil
+}
+
+// Upsert inserts a row, when it conflicts on conflictCols the updateCols
+// are overwritten with the new values instead, with no updateCols the
+// conflicting row is left alone, returns the number of rows affected
+func (t *Table) Upsert(row *Row, conflictCols []string, updateCols []string) (int64, error) {
+	return t.UpsertContext(context.Background(), row, conflictCols, updateCols)
+}
+
+// UpsertContext works as Upsert, the context cancels the query
+func (t *Table) UpsertContext(ctx context.Context, row *Row, conflictCols []string, updateCols []string) (int64, error) {
+	err := t.Validate()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, field := range append(append([]string{}, conflictCols...), updateCols...) {
+		err := validateField(field, t.cellNames())
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	st := t.client().newStatement()
+
+	templateVars, err := t.insertVars(row, st)
+	if err != nil {
+		return 0, err
+	}
+
+	templateVars["upsert"] = true
+	templateVars["conflict"] = conflictCols
+	templateVars["updates"] = updateCols
+
+	_, err = st.dialect.Upsert(conflictCols, updateCols, t.setFields(row))
+	if err != nil {
+		return 0, err
+	}
+
+	var b bytes.Buffer
+
+	err = render(&b, "insert", templateVars)
+	if err != nil {
+		return 0, t.queryError("execute template", "", err)
+	}
+
+	return t.client().execAffected(ctx, t.Name, b.String(), st)
+}
+
+// insertVars binds the cells set on a row for the insert template, unset
+// cells are left out so database defaults apply
+func (t *Table) insertVars(row *Row, st *statement) (map[string]interface{}, error) {
+	err := t.generateKeys(row)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := t.setFields(row)
+
+	placeholders, err := bindRow(st, row, fields)
+	if err != nil {
+		return nil, err
 	}
 
 	templateVars := make(map[string]interface{}, 0)
 	templateVars["table"] = t
 	templateVars["fields"] = fields
+	templateVars["values"] = [][]string{placeholders}
+	templateVars["stmt"] = st
 
-	var rowData = make([]interface{}, 0)
-	var placeholderCursor = 1
-
-	for _, col := range t.Cells {
-		c, ok := row.Cells[col.Name]
-		if ok {
-			if !c.Exclude {
-				switch c.Type {
-				case CellBool, CellString, CellInt, CellFloat, CellDate, CellDatetime, CellBytes:
-					value, err := c.GetValue()
-					if err != nil {
-						switch c.Type {
-						case CellBool:
-							rowData = append(rowData, sql.NullBool{})
-						case CellString:
-							rowData = append(rowData, sql.NullString{})
-						case CellInt:
-							rowData = append(rowData, sql.NullInt32{})
-						case CellFloat:
-							rowData = append(rowData, sql.NullFloat64{})
-						case CellDate:
-							rowData = append(rowData, sql.NullTime{})
-						case CellDatetime:
-							rowData = append(rowData, sql.NullTime{})
-						case CellBytes:
-							rowData = append(rowData, sql.NullString{})
-						}
-						placeholders = append(placeholders, "$"+strconv.Itoa(placeholderCursor))
-					} else {
-						rowData = append(rowData, value)
-						placeholders = append(placeholders, "$"+strconv.Itoa(placeholderCursor))
-					}
-				case CellBoolArray:
-					v, err := c.BoolArray()
-					if err == nil {
-						for _, val := range v {
-							rowData = append(rowData, val)
-						}
-
-						var p string
-						for i := 0; i < len(v); i++ {
-							p += "$" + strconv.Itoa(placeholderCursor)
-							if i < len(v)-1 {
-								p += ","
-								placeholderCursor++
-							}
-						}
-
-						placeholders = append(placeholders, "ARRAY["+p+"]::bool[]")
-					}
-				case CellStringArray:
-					v, err := c.StringArray()
-					if err == nil {
-						for _, val := range v {
-							rowData = append(rowData, val)
-						}
-
-						if len(v) > 0 {
-							var p string
-							for i := 0; i < len(v); i++ {
-								p += "$" + strconv.Itoa(placeholderCursor)
-								if i < len(v)-1 {
-									p += ","
-									placeholderCursor++
-								}
-							}
-
-							placeholders = append(placeholders, "ARRAY["+p+"]::text[]")
-						} else {
-							rowData = append(rowData, sql.NullString{})
-							placeholders = append(placeholders, "$"+strconv.Itoa(placeholderCursor))
-						}
-					}
-				case CellIntArray:
-					v, err := c.IntArray()
-					if err == nil {
-						for _, val := range v {
-							rowData = append(rowData, val)
-						}
-
-						var p string
-						for i := 0; i < len(v); i++ {
-							p += "$" + strconv.Itoa(placeholderCursor)
-							if i < len(v)-1 {
-								p += ","
-								placeholderCursor++
-							}
-						}
-
-						placeholders = append(placeholders, "ARRAY["+p+"]::integer[]")
-					}
-				case CellFloatArray:
-					v, err := c.FloatArray()
-					if err == nil {
-						for _, val := range v {
-							rowData = append(rowData, val)
-						}
-
-						var p string
-						for i := 0; i < len(v); i++ {
-							p += "$" + strconv.Itoa(placeholderCursor)
-							if i < len(v)-1 {
-								p += ","
-								placeholderCursor++
-							}
-						}
-
-						placeholders = append(placeholders, "ARRAY["+p+"]::float[]")
-					}
-				case CellDateArray:
-					v, err := c.DateArray()
-					if err == nil {
-						for _, val := range v {
-							rowData = append(rowData, val)
-						}
-
-						var p string
-						for i := 0; i < len(v); i++ {
-							p += "$" + strconv.Itoa(placeholderCursor)
-							if i < len(v)-1 {
-								p += ","
-								placeholderCursor++
-							}
-						}
-
-						placeholders = append(placeholders, "ARRAY["+p+"]::date[]")
-					}
-				case CellDatetimeArray:
-					v, err := c.DateArray()
-					if err == nil {
-						for _, val := range v {
-							rowData = append(rowData, val)
-						}
-
-						var p string
-						for i := 0; i < len(v); i++ {
-							p += "$" + strconv.Itoa(placeholderCursor)
-							if i < len(v)-1 {
-								p += ","
-								placeholderCursor++
-							}
-						}
-
-						placeholders = append(placeholders, "ARRAY["+p+"]::datetime[]")
-					}
-				}
-
-				placeholderCursor++
-			}
+	return templateVars, nil
+}
+
+// generateKeys fills the unset primary key cells of a row that declare a
+// generator
+func (t *Table) generateKeys(row *Row) error {
+	for _, c := range t.Cells {
+		if !c.PrimaryKey || c.Generate == nil {
+			continue
 		}
+
+		rc, ok := row.Cells[c.Name]
+		if !ok || rc.IsSet() {
+			continue
+		}
+
+		u, err := c.Generate()
+		if err != nil {
+			return err
+		}
+
+		err = rc.SetUUID(u)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Update writes the cells set on row to every record matching the query
+// and returns the number of rows affected
+func (t *Table) Update(row *Row, q Query) (int64, error) {
+	return t.UpdateContext(context.Background(), row, q)
+}
+
+// UpdateContext works as Update, the context cancels the query
+func (t *Table) UpdateContext(ctx context.Context, row *Row, q Query) (int64, error) {
+	b, st, err := t.renderUpdate(row, q, false)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.client().execAffected(ctx, t.Name, b, st)
+}
+
+// UpdateReturning works as Update but gives back the updated rows
+func (t *Table) UpdateReturning(row *Row, q Query) (*Rows, error) {
+	return t.UpdateReturningContext(context.Background(), row, q)
+}
+
+// UpdateReturningContext works as UpdateReturning, the context cancels the query
+func (t *Table) UpdateReturningContext(ctx context.Context, row *Row, q Query) (*Rows, error) {
+	if !t.client().dialect.SupportsReturning() {
+		return nil, errors.New("RETURNING is not supported by " + t.client().dialect.Name())
+	}
+
+	b, st, err := t.renderUpdate(row, q, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.queryRows(ctx, b, st)
+}
+
+// Delete removes every record matching the query and returns the number
+// of rows affected, the query needs at least one filter, use DeleteAll to
+// empty the table
+func (t *Table) Delete(q Query) (int64, error) {
+	return t.DeleteContext(context.Background(), q)
+}
+
+// DeleteContext works as Delete, the context cancels the query
+func (t *Table) DeleteContext(ctx context.Context, q Query) (int64, error) {
+	b, st, err := t.renderDelete(q, false, false)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.client().execAffected(ctx, t.Name, b, st)
+}
+
+// DeleteAll removes every record of the table and returns the number of
+// rows affected
+func (t *Table) DeleteAll() (int64, error) {
+	return t.DeleteAllContext(context.Background())
+}
+
+// DeleteAllContext works as DeleteAll, the context cancels the query
+func (t *Table) DeleteAllContext(ctx context.Context) (int64, error) {
+	b, st, err := t.renderDelete(Query{}, false, true)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.client().execAffected(ctx, t.Name, b, st)
+}
+
+// DeleteReturning works as Delete but gives back the deleted rows
+func (t *Table) DeleteReturning(q Query) (*Rows, error) {
+	return t.DeleteReturningContext(context.Background(), q)
+}
+
+// DeleteReturningContext works as DeleteReturning, the context cancels the query
+func (t *Table) DeleteReturningContext(ctx context.Context, q Query) (*Rows, error) {
+	if !t.client().dialect.SupportsReturning() {
+		return nil, errors.New("RETURNING is not supported by " + t.client().dialect.Name())
+	}
+
+	b, st, err := t.renderDelete(q, true, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.queryRows(ctx, b, st)
+}
+
+// renderUpdate builds the UPDATE statement for the cells set on row
+func (t *Table) renderUpdate(row *Row, q Query, returning bool) (string, *statement, error) {
+	err := t.Validate()
+	if err != nil {
+		return "", nil, err
 	}
 
+	err = q.Validate(t)
+	if err != nil {
+		return "", nil, err
+	}
+
+	fields := t.setFields(row)
+
+	if len(fields) == 0 {
+		return "", nil, &ValidationError{Field: t.Name, Reason: "no cells set to update"}
+	}
+
+	st := t.client().newStatement()
+	st.table = t
+
+	placeholders, err := bindRow(st, row, fields)
+	if err != nil {
+		return "", nil, err
+	}
+
+	templateVars := make(map[string]interface{}, 0)
+	templateVars["table"] = t
+	templateVars["fields"] = fields
 	templateVars["placeholders"] = placeholders
-	templateVars["returning"] = returning
+	templateVars["query"] = q
+	templateVars["stmt"] = st
+
+	if returning {
+		templateVars["returning"] = t.selectFields()
+	}
 
 	var b bytes.Buffer
 
-	err := tmpl.ExecuteTemplate(&b, "insert", templateVars)
+	err = render(&b, "update", templateVars)
 	if err != nil {
-		return 0, errors.New("Failure to execute template")
+		return "", nil, t.queryError("execute template", "", err)
 	}
 
-	var lastInsert int64
+	return b.String(), st, nil
+}
 
-	err = db.QueryRow(b.String(), rowData...).Scan(&lastInsert)
+// renderDelete builds the DELETE statement for a query, a query without
+// filters is refused unless all is set
+func (t *Table) renderDelete(q Query, returning bool, all bool) (string, *statement, error) {
+	err := t.Validate()
 	if err != nil {
-		return 0, errors.New("Failure to execute query")
+		return "", nil, err
 	}
 
-	return lastInsert, nil
+	if len(q.Filters) == 0 && !all {
+		return "", nil, &ValidationError{Field: t.Name, Reason: "delete without filters, use DeleteAll"}
+	}
+
+	err = q.Validate(t)
+	if err != nil {
+		return "", nil, err
+	}
+
+	st := t.client().newStatement()
+	st.table = t
+
+	templateVars := make(map[string]interface{}, 0)
+	templateVars["table"] = t
+	templateVars["query"] = q
+	templateVars["stmt"] = st
+
+	if returning {
+		templateVars["returning"] = t.selectFields()
+	}
+
+	var b bytes.Buffer
+
+	err = render(&b, "delete", templateVars)
+	if err != nil {
+		return "", nil, t.queryError("execute template", "", err)
+	}
+
+	return b.String(), st, nil
+}
+
+// cellNames is the set of cell names in the table
+func (t *Table) cellNames() map[string]bool {
+	names := make(map[string]bool)
+
+	for _, c := range t.Cells {
+		names[c.Name] = true
+	}
+
+	return names
+}
+
+// cellType is the type of a named cell, ok is false when there is none
+func (t *Table) cellType(name string) (CellType, bool) {
+	for _, c := range t.Cells {
+		if c.Name == name {
+			return c.Type, true
+		}
+	}
+
+	return 0, false
+}
+
+// primaryKeys lists the cells declared as the primary key
+func (t *Table) primaryKeys() []string {
+	keys := make([]string, 0)
+
+	for _, c := range t.Cells {
+		if c.PrimaryKey {
+			keys = append(keys, c.Name)
+		}
+	}
+
+	return keys
+}
+
+// selectFields lists the cells that are read back from the database
+func (t *Table) selectFields() []string {
+	fields := make([]string, 0)
+
+	for _, c := range t.Cells {
+		if !c.Exclude {
+			fields = append(fields, c.Name)
+		}
+	}
+
+	return fields
+}
+
+// queryRows runs a rendered statement and scans the rows it returns
+func (t *Table) queryRows(ctx context.Context, q string, st *statement) (*Rows, error) {
+	rows, err := t.session(ctx).exec.QueryContext(ctx, q, st.Args()...)
+	if err != nil {
+		return nil, t.queryError("execute query", q, err)
+	}
+	defer rows.Close()
+
+	return t.scanRows(ctx, q, rows, t.selectFields())
 }
@@ -0,0 +1,161 @@
+package scaffold
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// txKey is the context key the DB of a running transaction is kept under
+type txKey struct{}
+
+// WithTx runs fn inside a transaction on the package level DB
+func WithTx(ctx context.Context, fn func(ctx context.Context, tx *DB) error) error {
+	return std.WithTx(ctx, fn)
+}
+
+// WithTxOptions runs fn inside a transaction on the package level DB
+// with the given isolation level and read only flag
+func WithTxOptions(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx *DB) error) error {
+	return std.WithTxOptions(ctx, opts, fn)
+}
+
+// WithTx runs fn inside a transaction, it commits when fn returns nil and
+// rolls back when fn returns an error or panics. The DB handed to fn is
+// bound to the transaction and so is the context, Context methods given
+// that context run on the transaction, for tables bound to this DB as
+// well. Methods without a context run outside of it, with a single
+// connection pool they wait on the transaction forever. Calling WithTx
+// again with the context opens a savepoint instead of a new transaction
+func (s *DB) WithTx(ctx context.Context, fn func(ctx context.Context, tx *DB) error) error {
+	return s.WithTxOptions(ctx, nil, fn)
+}
+
+// WithTxOptions works as WithTx, opts sets the isolation level and read
+// only flag of the transaction, savepoints can not change either
+func (s *DB) WithTxOptions(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx *DB) error) error {
+	bound := s.bound(ctx)
+
+	if bound.tx != nil {
+		if opts != nil && (opts.Isolation != sql.LevelDefault || opts.ReadOnly) {
+			return errors.New("Transaction options can not be set on a savepoint")
+		}
+
+		return bound.savepoint(ctx, fn)
+	}
+
+	if s.conn == nil {
+		return errors.New("No database connection")
+	}
+
+	tx, err := s.conn.BeginTx(ctx, opts)
+	if err != nil {
+		return s.queryError("begin transaction", "", "", err)
+	}
+
+	err = run(ctx, s.bindTx(tx, 0), fn, tx.Rollback)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return s.queryError("commit transaction", "", "", err)
+	}
+
+	return nil
+}
+
+// Tx returns the transaction the DB is bound to, nil outside WithTx
+func (s *DB) Tx() *sql.Tx {
+	return s.tx
+}
+
+// savepoint runs fn inside a savepoint of the running transaction
+func (s *DB) savepoint(ctx context.Context, fn func(ctx context.Context, tx *DB) error) error {
+	name := fmt.Sprintf("scaffold_sp_%d", s.depth+1)
+
+	_, err := s.tx.ExecContext(ctx, "SAVEPOINT "+name)
+	if err != nil {
+		return s.queryError("create savepoint", "", "SAVEPOINT "+name, err)
+	}
+
+	rollback := func() error {
+		_, err := s.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+		return err
+	}
+
+	err = run(ctx, s.bindTx(s.tx, s.depth+1), fn, rollback)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	if err != nil {
+		return s.queryError("release savepoint", "", "RELEASE SAVEPOINT "+name, err)
+	}
+
+	return nil
+}
+
+// run calls fn with a context carrying tx and rolls back when it fails or
+// panics, panics are passed on once the rollback is done
+func run(ctx context.Context, tx *DB, fn func(ctx context.Context, tx *DB) error, rollback func() error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			rollback()
+			panic(p)
+		}
+	}()
+
+	err = fn(context.WithValue(ctx, txKey{}, tx), tx)
+	if err != nil {
+		rollback()
+		return err
+	}
+
+	return nil
+}
+
+// bindTx makes a DB that runs on tx, with the tables of s bound to it
+func (s *DB) bindTx(tx *sql.Tx, depth int) *DB {
+	c := New(s.conn, s.dialect)
+
+	c.exec = tx
+	c.tx = tx
+	c.depth = depth
+	c.origin = s.originDB()
+	c.strictTypes = s.strictTypes
+	c.json = s.json
+	c.location = s.location
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, t := range s.tables {
+		c.Register(t)
+	}
+
+	return c
+}
+
+// originDB is the DB transactions bound to s were opened from
+func (s *DB) originDB() *DB {
+	if s.origin != nil {
+		return s.origin
+	}
+
+	return s
+}
+
+// bound is the DB to run on for ctx, the transaction ctx carries when it
+// was opened from the same DB as s, s otherwise
+func (s *DB) bound(ctx context.Context) *DB {
+	tx, ok := ctx.Value(txKey{}).(*DB)
+	if ok && tx.originDB() == s.originDB() {
+		return tx
+	}
+
+	return s
+}
@@ -1,11 +1,31 @@
 package scaffold
 
 import (
+	"database/sql"
 	"errors"
+	"strconv"
 
 	"github.com/lib/pq"
 )
 
+func init() {
+	registerCellType(CellBool, CellTypeDef{
+		Name:    "bool",
+		New:     func() SQLCell { return NewSQLBool() },
+		DBTypes: []string{"BOOL", "BOOLEAN", "BIT"},
+		Columns: map[string]string{"": "BOOLEAN"},
+		Null:    sql.NullBool{},
+	})
+
+	registerCellType(CellBoolArray, CellTypeDef{
+		Name:    "bool array",
+		New:     func() SQLCell { return NewSQLBoolArray() },
+		Array:   true,
+		DBTypes: []string{"BOOL[]", "BIT[]", "_BOOL"},
+		Columns: map[string]string{"postgres": "BOOL[]", "mysql": "JSON"},
+	})
+}
+
 // SQLBool representation of SQL
 type SQLBool struct {
 	Valid bool
@@ -63,6 +83,11 @@ func (x *SQLBoolArray) Target() interface{} {
 	return pq.Array(&x.Value)
 }
 
+// JSONTarget gets a scannable target for SQLBoolArray stored as JSON
+func (x *SQLBoolArray) JSONTarget() interface{} {
+	return &jsonArray{Valid: &x.Valid, Value: &x.Value}
+}
+
 // Scan interface->Bool
 func (x *SQLBool) Scan(data interface{}) error {
 	switch data.(type) {
@@ -72,6 +97,16 @@ func (x *SQLBool) Scan(data interface{}) error {
 			x.Valid = true
 			x.Value = v
 		}
+	case int64:
+		x.Valid = true
+		x.Value = data.(int64) != 0
+	case []byte, string:
+		v, err := strconv.ParseBool(asString(data))
+		if err != nil {
+			return errors.New("Incompatible type")
+		}
+		x.Valid = true
+		x.Value = v
 	case nil:
 		x.Valid = false
 		x.Value = false
@@ -1,11 +1,32 @@
 package scaffold
 
 import (
+	"database/sql/driver"
 	"errors"
 
 	"github.com/lib/pq"
 )
 
+func init() {
+	registerCellType(CellBytes, CellTypeDef{
+		Name:    "bytes",
+		New:     func() SQLCell { return NewSQLBytes() },
+		DBTypes: []string{"BYTEA", "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB", "BINARY", "VARBINARY"},
+		Columns: map[string]string{"postgres": "BYTEA", "sqlite": "BLOB", "mysql": "LONGBLOB"},
+		// mysql only indexes BLOB with a prefix length
+		KeyColumns: map[string]string{"mysql": "VARBINARY(255)"},
+		Null:       nullBytes{},
+	})
+
+	registerCellType(CellBytesArray, CellTypeDef{
+		Name:    "bytes array",
+		New:     func() SQLCell { return NewSQLByteArray() },
+		Array:   true,
+		DBTypes: []string{"BYTEA[]", "_BYTEA"},
+		Columns: map[string]string{"postgres": "BYTEA[]", "mysql": "JSON"},
+	})
+}
+
 // SQLBytes representation of SQL
 type SQLBytes struct {
 	Valid bool
@@ -63,15 +84,31 @@ func (x *SQLBytesArray) Target() interface{} {
 	return pq.Array(&x.Value)
 }
 
-// Scan interface->Byte
+// JSONTarget gets a scannable target for SQLBytesArray stored as JSON,
+// the values are base64 strings
+func (x *SQLBytesArray) JSONTarget() interface{} {
+	return &jsonArray{Valid: &x.Valid, Value: &x.Value}
+}
+
+// nullBytes binds NULL to a binary column, a nil []byte would bind an
+// empty value instead
+type nullBytes struct{}
+
+// Value nullBytes->driver
+func (x nullBytes) Value() (driver.Value, error) {
+	return nil, nil
+}
+
+// Scan interface->Byte, drivers may reuse the scanned buffer so it is
+// copied
 func (x *SQLBytes) Scan(data interface{}) error {
-	switch data.(type) {
+	switch v := data.(type) {
 	case []byte:
-		v, ok := data.([]byte)
-		if ok {
-			x.Valid = true
-			x.Value = v
-		}
+		x.Valid = true
+		x.Value = append([]byte{}, v...)
+	case string:
+		x.Valid = true
+		x.Value = []byte(v)
 	case nil:
 		x.Valid = false
 		x.Value = []byte("")
@@ -1,12 +1,40 @@
 package scaffold
 
 import (
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"time"
 
 	"github.com/lib/pq"
 )
 
+func init() {
+	registerCellType(CellDate, CellTypeDef{
+		Name:    "date",
+		New:     func() SQLCell { return NewSQLDate() },
+		DBTypes: []string{"DATE"},
+		Columns: map[string]string{"": "DATE"},
+		Null:    sql.NullTime{},
+		JSON: func(v interface{}, _ JSONOptions) ([]byte, error) {
+			x, ok := v.(time.Time)
+			if !ok {
+				return nil, valueMismatch(v, "time.Time")
+			}
+
+			return json.Marshal(x.Format("2006-01-02"))
+		},
+	})
+
+	registerCellType(CellDateArray, CellTypeDef{
+		Name:    "date array",
+		New:     func() SQLCell { return NewSQLDateArray() },
+		Array:   true,
+		DBTypes: []string{"DATE[]", "_DATE"},
+		Columns: map[string]string{"postgres": "DATE[]", "mysql": "JSON"},
+	})
+}
+
 // SQLDate representation of SQL
 type SQLDate struct {
 	Valid bool
@@ -64,6 +92,11 @@ func (x *SQLDateArray) Target() interface{} {
 	return pq.Array(&x.Value)
 }
 
+// JSONTarget gets a scannable target for SQLDateArray stored as JSON
+func (x *SQLDateArray) JSONTarget() interface{} {
+	return &jsonArray{Valid: &x.Valid, Value: &x.Value}
+}
+
 // Scan interface->Date
 func (x *SQLDate) Scan(data interface{}) error {
 	switch data.(type) {
@@ -1,16 +1,131 @@
 package scaffold
 
 import (
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"time"
 
 	"github.com/lib/pq"
 )
 
+func init() {
+	registerCellType(CellDatetime, CellTypeDef{
+		Name:    "datetime",
+		New:     func() SQLCell { return NewSQLDatetime() },
+		DBTypes: []string{"DATETIME", "SMALLDATETIME", "TIMESTAMP", "TIMESTAMP WITHOUT TIME ZONE"},
+		Columns: map[string]string{"postgres": "TIMESTAMP", "sqlite": "DATETIME", "mysql": "DATETIME(6)"},
+		Null:    sql.NullTime{},
+		JSON:    timeJSON,
+	})
+
+	registerCellType(CellDatetimeArray, CellTypeDef{
+		Name:    "datetime array",
+		New:     func() SQLCell { return NewSQLDatetimeArray() },
+		Array:   true,
+		DBTypes: []string{"DATETIME[]", "SMALLDATETIME[]", "TIMESTAMP[]", "_TIMESTAMP"},
+		Columns: map[string]string{"postgres": "TIMESTAMP[]", "mysql": "JSON"},
+	})
+
+	registerCellType(CellDatetimeTZ, CellTypeDef{
+		Name:    "datetime with time zone",
+		New:     func() SQLCell { return NewSQLDatetimeTZ() },
+		DBTypes: []string{"TIMESTAMPTZ", "TIMESTAMP WITH TIME ZONE", "DATETIMEOFFSET"},
+		// mysql has no zoned type, values are written in UTC
+		Columns: map[string]string{"postgres": "TIMESTAMPTZ", "sqlite": "TIMESTAMPTZ", "mysql": "DATETIME(6)"},
+		Null:    sql.NullTime{},
+		JSON:    timeJSON,
+	})
+
+	registerCellType(CellDatetimeTZArray, CellTypeDef{
+		Name:    "datetime with time zone array",
+		New:     func() SQLCell { return NewSQLDatetimeArray() },
+		Array:   true,
+		DBTypes: []string{"TIMESTAMPTZ[]", "_TIMESTAMPTZ"},
+		Columns: map[string]string{"postgres": "TIMESTAMPTZ[]", "mysql": "JSON"},
+	})
+}
+
+// ISO-8601 layouts datetimes are stored with as text, the fixed width
+// fraction keeps the text in time order
+const (
+	isoDatetime   = "2006-01-02T15:04:05.000000"
+	isoDatetimeTZ = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+// timeLayouts are read from text, zoned layouts first
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// timeJSON renders a datetime with its offset, datetimes read from the
+// database are in the location of the DB
+func timeJSON(v interface{}, _ JSONOptions) ([]byte, error) {
+	x, ok := v.(time.Time)
+	if !ok {
+		return nil, valueMismatch(v, "time.Time")
+	}
+
+	return json.Marshal(x.Format(time.RFC3339Nano))
+}
+
+// locator is a value holder that reads datetimes in a location
+type locator interface {
+	setLocation(loc *time.Location)
+}
+
+// orUTC is loc, UTC when it is nil
+func orUTC(loc *time.Location) *time.Location {
+	if loc == nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// zonedTimeCell reports whether a scalar datetime cell type keeps its time
+// zone, ok is false for other types
+func zonedTimeCell(t CellType) (zoned bool, ok bool) {
+	switch t {
+	case CellDatetime:
+		return false, true
+	case CellDatetimeTZ:
+		return true, true
+	}
+
+	return false, false
+}
+
+// wallClock moves the clock reading of v into loc, keeping the digits
+func wallClock(v time.Time, loc *time.Location) time.Time {
+	return time.Date(v.Year(), v.Month(), v.Day(), v.Hour(), v.Minute(), v.Second(), v.Nanosecond(), loc)
+}
+
+// parseTime reads datetime text, text without an offset is taken as the
+// wall clock of loc
+func parseTime(s string, loc *time.Location) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		v, err := time.ParseInLocation(layout, s, loc)
+		if err == nil {
+			return v, nil
+		}
+	}
+
+	return time.Time{}, errors.New("Incompatible type")
+}
+
 // SQLDatetime representation of SQL
 type SQLDatetime struct {
 	Valid bool
 	Value time.Time
+	loc   *time.Location
 }
 
 // SQLDatetimeArray representation of SQL
@@ -36,6 +151,21 @@ func NewSQLDatetimeArray() *SQLDatetimeArray {
 	return x
 }
 
+// SQLDatetimeTZ representation of SQL
+type SQLDatetimeTZ struct {
+	Valid bool
+	Value time.Time
+	loc   *time.Location
+}
+
+// NewSQLDatetimeTZ makes a SQLDatetimeTZ
+func NewSQLDatetimeTZ() *SQLDatetimeTZ {
+	x := new(SQLDatetimeTZ)
+	x.Valid = false
+
+	return x
+}
+
 // Raw Date->Raw
 func (x *SQLDatetime) Raw() (interface{}, error) {
 	if !x.Valid {
@@ -59,20 +189,80 @@ func (x *SQLDatetime) Target() interface{} {
 	return x
 }
 
+// Raw DatetimeTZ->Raw
+func (x *SQLDatetimeTZ) Raw() (interface{}, error) {
+	if !x.Valid {
+		return x.Value, errors.New("Invalid value")
+	}
+
+	return x.Value, nil
+}
+
+// Target gets the scannable target for SQLDatetimeTZ
+func (x *SQLDatetimeTZ) Target() interface{} {
+	return x
+}
+
 // Target gets the scannable target for SQLDatetimeArray
 func (x *SQLDatetimeArray) Target() interface{} {
 	return pq.Array(&x.Value)
 }
 
-// Scan interface->Date
+// JSONTarget gets a scannable target for SQLDatetimeArray stored as JSON
+func (x *SQLDatetimeArray) JSONTarget() interface{} {
+	return &jsonArray{Valid: &x.Valid, Value: &x.Value}
+}
+
+// setLocation sets the location the wall clock is read in
+func (x *SQLDatetime) setLocation(loc *time.Location) {
+	x.loc = loc
+}
+
+// setLocation sets the location values are converted to
+func (x *SQLDatetimeTZ) setLocation(loc *time.Location) {
+	x.loc = loc
+}
+
+// Scan interface->Datetime, drivers hand back the stored wall clock in
+// UTC so it is moved into the location of the DB
 func (x *SQLDatetime) Scan(data interface{}) error {
-	switch data.(type) {
+	loc := orUTC(x.loc)
+
+	switch v := data.(type) {
+	case time.Time:
+		x.Valid = true
+		x.Value = wallClock(v, loc)
+	case []byte, string:
+		t, err := parseTime(asString(v), loc)
+		if err != nil {
+			return err
+		}
+		x.Valid = true
+		x.Value = t.In(loc)
+	case nil:
+		x.Valid = false
+		x.Value = time.Time{}
+	default:
+		return errors.New("Incompatible type")
+	}
+	return nil
+}
+
+// Scan interface->DatetimeTZ, converted to the location of the DB
+func (x *SQLDatetimeTZ) Scan(data interface{}) error {
+	loc := orUTC(x.loc)
+
+	switch v := data.(type) {
 	case time.Time:
-		v, ok := data.(time.Time)
-		if ok {
-			x.Valid = true
-			x.Value = v
+		x.Valid = true
+		x.Value = v.In(loc)
+	case []byte, string:
+		t, err := parseTime(asString(v), time.UTC)
+		if err != nil {
+			return err
 		}
+		x.Valid = true
+		x.Value = t.In(loc)
 	case nil:
 		x.Valid = false
 		x.Value = time.Time{}
@@ -0,0 +1,171 @@
+package scaffold
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+func init() {
+	registerCellType(CellDecimal, CellTypeDef{
+		Name:    "decimal",
+		New:     func() SQLCell { return NewSQLDecimal() },
+		DBTypes: []string{"DECIMAL", "NUMERIC", "MONEY"},
+		// sqlite keeps decimals as text, NUMERIC affinity would store reals,
+		// so they only compare for equality there
+		Columns:      map[string]string{"postgres": "NUMERIC", "sqlite": "TEXT", "mysql": "DECIMAL(65,30)"},
+		SizedColumns: map[string]string{"postgres": "NUMERIC(%d,%d)", "mysql": "DECIMAL(%d,%d)"},
+		Null:         sql.NullString{},
+		JSON: func(v interface{}, opts JSONOptions) ([]byte, error) {
+			x, ok := v.(decimal.Decimal)
+			if !ok {
+				return nil, valueMismatch(v, "decimal.Decimal")
+			}
+
+			return decimalJSON(x, opts), nil
+		},
+		Unordered: []string{"sqlite"},
+	})
+
+	registerCellType(CellDecimalArray, CellTypeDef{
+		Name:         "decimal array",
+		New:          func() SQLCell { return NewSQLDecimalArray() },
+		Array:        true,
+		DBTypes:      []string{"DECIMAL[]", "NUMERIC[]", "MONEY[]", "_NUMERIC", "_MONEY"},
+		Columns:      map[string]string{"postgres": "NUMERIC[]", "mysql": "JSON"},
+		SizedColumns: map[string]string{"postgres": "NUMERIC(%d,%d)[]"},
+		JSON: func(v interface{}, opts JSONOptions) ([]byte, error) {
+			list, ok := v.([]decimal.Decimal)
+			if !ok {
+				return nil, valueMismatch(v, "[]decimal.Decimal")
+			}
+			parts := make([]string, 0, len(list))
+
+			for _, d := range list {
+				parts = append(parts, string(decimalJSON(d, opts)))
+			}
+
+			return []byte("[" + strings.Join(parts, ",") + "]"), nil
+		},
+	})
+}
+
+// decimalJSON renders a decimal as a JSON string or number
+func decimalJSON(d decimal.Decimal, opts JSONOptions) []byte {
+	if opts.DecimalNumbers {
+		return []byte(d.String())
+	}
+
+	b, _ := json.Marshal(d.String())
+	return b
+}
+
+// SQLDecimal representation of SQL
+type SQLDecimal struct {
+	Valid bool
+	Value decimal.Decimal
+}
+
+// SQLDecimalArray representation of SQL
+type SQLDecimalArray struct {
+	Valid bool
+	Value []decimal.Decimal
+}
+
+// NewSQLDecimal makes a SQLDecimal
+func NewSQLDecimal() *SQLDecimal {
+	x := new(SQLDecimal)
+	x.Valid = false
+
+	return x
+}
+
+// NewSQLDecimalArray makes a SQLDecimalArray
+func NewSQLDecimalArray() *SQLDecimalArray {
+	x := new(SQLDecimalArray)
+	x.Valid = true
+	x.Value = make([]decimal.Decimal, 0)
+
+	return x
+}
+
+// Raw Decimal->Raw
+func (x *SQLDecimal) Raw() (interface{}, error) {
+	if !x.Valid {
+		return x.Value, errors.New("Invalid value")
+	}
+
+	return x.Value, nil
+}
+
+// Raw DecimalArray->Raw
+func (x *SQLDecimalArray) Raw() (interface{}, error) {
+	if !x.Valid {
+		return x.Value, errors.New("Invalid value")
+	}
+
+	return x.Value, nil
+}
+
+// Target gets the scannable target for SQLDecimal
+func (x *SQLDecimal) Target() interface{} {
+	return x
+}
+
+// Target gets the scannable target for SQLDecimalArray
+func (x *SQLDecimalArray) Target() interface{} {
+	return pq.Array(&x.Value)
+}
+
+// JSONTarget gets a scannable target for SQLDecimalArray stored as JSON
+func (x *SQLDecimalArray) JSONTarget() interface{} {
+	return &jsonArray{Valid: &x.Valid, Value: &x.Value}
+}
+
+// Scan interface->Decimal, text is parsed exactly, reals from sqlite are
+// taken as they were stored
+func (x *SQLDecimal) Scan(data interface{}) error {
+	switch v := data.(type) {
+	case []byte, string:
+		d, err := parseDecimal(asString(v))
+		if err != nil {
+			return errors.New("Incompatible type")
+		}
+		x.Valid = true
+		x.Value = d
+	case int64:
+		x.Valid = true
+		x.Value = decimal.NewFromInt(v)
+	case float64:
+		x.Valid = true
+		x.Value = decimal.NewFromFloat(v)
+	case nil:
+		x.Valid = false
+		x.Value = decimal.Zero
+	default:
+		return errors.New("Incompatible type")
+	}
+	return nil
+}
+
+// parseDecimal reads a decimal, falling back to dropping the currency
+// symbols and group separators postgres writes for MONEY
+func parseDecimal(s string) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(s)
+	if err == nil {
+		return d, nil
+	}
+
+	cleaned := strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '-' || r == '.' {
+			return r
+		}
+		return -1
+	}, s)
+
+	return decimal.NewFromString(cleaned)
+}
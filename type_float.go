@@ -1,11 +1,31 @@
 package scaffold
 
 import (
+	"database/sql"
 	"errors"
+	"strconv"
 
 	"github.com/lib/pq"
 )
 
+func init() {
+	registerCellType(CellFloat, CellTypeDef{
+		Name:    "float",
+		New:     func() SQLCell { return NewSQLFloat() },
+		DBTypes: []string{"FLOAT", "FLOAT4", "FLOAT8", "REAL", "DOUBLE"},
+		Columns: map[string]string{"postgres": "DOUBLE PRECISION", "sqlite": "REAL", "mysql": "DOUBLE"},
+		Null:    sql.NullFloat64{},
+	})
+
+	registerCellType(CellFloatArray, CellTypeDef{
+		Name:    "float array",
+		New:     func() SQLCell { return NewSQLFloatArray() },
+		Array:   true,
+		DBTypes: []string{"FLOAT[]", "FLOAT4[]", "FLOAT8[]", "_FLOAT4", "_FLOAT8"},
+		Columns: map[string]string{"postgres": "FLOAT8[]", "mysql": "JSON"},
+	})
+}
+
 // SQLFloat representation of SQL
 type SQLFloat struct {
 	Valid bool
@@ -63,6 +83,11 @@ func (x *SQLFloatArray) Target() interface{} {
 	return pq.Array(&x.Value)
 }
 
+// JSONTarget gets a scannable target for SQLFloatArray stored as JSON
+func (x *SQLFloatArray) JSONTarget() interface{} {
+	return &jsonArray{Valid: &x.Valid, Value: &x.Value}
+}
+
 // Scan interface->Int
 func (x *SQLFloat) Scan(data interface{}) error {
 	switch data.(type) {
@@ -72,6 +97,16 @@ func (x *SQLFloat) Scan(data interface{}) error {
 			x.Valid = true
 			x.Value = v
 		}
+	case int64:
+		x.Valid = true
+		x.Value = float64(data.(int64))
+	case []byte, string:
+		v, err := strconv.ParseFloat(asString(data), 64)
+		if err != nil {
+			return errors.New("Incompatible type")
+		}
+		x.Valid = true
+		x.Value = v
 	case nil:
 		x.Valid = false
 		x.Value = 0
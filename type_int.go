@@ -1,11 +1,34 @@
 package scaffold
 
 import (
+	"database/sql"
 	"errors"
+	"strconv"
 
 	"github.com/lib/pq"
 )
 
+func init() {
+	registerCellType(CellInt, CellTypeDef{
+		Name: "int",
+		New:  func() SQLCell { return NewSQLInt() },
+		DBTypes: []string{
+			"INT", "INT2", "INT4", "INT8", "INTEGER", "BIGINT", "SMALLINT", "TINYINT", "MEDIUMINT",
+			"UNSIGNED TINYINT", "UNSIGNED SMALLINT", "UNSIGNED MEDIUMINT", "UNSIGNED INT", "YEAR",
+		},
+		Columns: map[string]string{"postgres": "BIGINT", "sqlite": "INTEGER", "mysql": "BIGINT"},
+		Null:    sql.NullInt64{},
+	})
+
+	registerCellType(CellIntArray, CellTypeDef{
+		Name:    "int array",
+		New:     func() SQLCell { return NewSQLIntArray() },
+		Array:   true,
+		DBTypes: []string{"INT[]", "INT4[]", "INT8[]", "BIGINT[]", "SMALLINT[]", "_INT2", "_INT4", "_INT8"},
+		Columns: map[string]string{"postgres": "BIGINT[]", "mysql": "JSON"},
+	})
+}
+
 // SQLInt representation of SQL
 type SQLInt struct {
 	Valid bool
@@ -63,6 +86,11 @@ func (x *SQLIntArray) Target() interface{} {
 	return pq.Array(&x.Value)
 }
 
+// JSONTarget gets a scannable target for SQLIntArray stored as JSON
+func (x *SQLIntArray) JSONTarget() interface{} {
+	return &jsonArray{Valid: &x.Valid, Value: &x.Value}
+}
+
 // Scan interface->Int
 func (x *SQLInt) Scan(data interface{}) error {
 	switch data.(type) {
@@ -72,6 +100,13 @@ func (x *SQLInt) Scan(data interface{}) error {
 			x.Valid = true
 			x.Value = v
 		}
+	case []byte, string:
+		v, err := strconv.ParseInt(asString(data), 10, 64)
+		if err != nil {
+			return errors.New("Incompatible type")
+		}
+		x.Valid = true
+		x.Value = v
 	case nil:
 		x.Valid = false
 		x.Value = 0
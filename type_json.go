@@ -0,0 +1,97 @@
+package scaffold
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+)
+
+func init() {
+	registerCellType(CellJSON, CellTypeDef{
+		Name:    "json",
+		New:     func() SQLCell { return NewSQLJSON() },
+		DBTypes: []string{"JSON", "JSONB"},
+		Columns: map[string]string{"postgres": "JSONB", "sqlite": "TEXT", "mysql": "JSON"},
+		Null:    sql.NullString{},
+		// bound as text, drivers send bytes as binary data
+		Bind: func(v interface{}) (interface{}, error) {
+			b, err := jsonBytes(v)
+			if err != nil {
+				return nil, err
+			}
+
+			return string(b), nil
+		},
+		JSON: func(v interface{}, _ JSONOptions) ([]byte, error) {
+			return jsonBytes(v)
+		},
+	})
+}
+
+// jsonBytes is the JSON text held by a cell, text that is not JSON is
+// refused so it is never embedded as is
+func jsonBytes(v interface{}) ([]byte, error) {
+	var b []byte
+
+	switch x := v.(type) {
+	case []byte:
+		b = x
+	case string:
+		b = []byte(x)
+	default:
+		return nil, valueMismatch(v, "JSON text")
+	}
+
+	if !json.Valid(b) {
+		return nil, errors.New("invalid JSON")
+	}
+
+	return b, nil
+}
+
+// SQLJSON representation of SQL
+type SQLJSON struct {
+	Valid bool
+	Value []byte
+}
+
+// NewSQLJSON makes a SQLJSON
+func NewSQLJSON() *SQLJSON {
+	x := new(SQLJSON)
+	x.Valid = false
+
+	return x
+}
+
+// Raw JSON->Raw
+func (x *SQLJSON) Raw() (interface{}, error) {
+	if !x.Valid {
+		return x.Value, errors.New("Invalid value")
+	}
+
+	return x.Value, nil
+}
+
+// Target gets the scannable target for SQLJSON
+func (x *SQLJSON) Target() interface{} {
+	return x
+}
+
+// Scan interface->JSON
+func (x *SQLJSON) Scan(data interface{}) error {
+	switch v := data.(type) {
+	case nil:
+		x.Valid = false
+		return nil
+	case []byte:
+		x.Value = append([]byte{}, v...)
+		x.Valid = true
+		return nil
+	case string:
+		x.Value = []byte(v)
+		x.Valid = true
+		return nil
+	}
+
+	return errors.New("Incompatible type")
+}
@@ -1,11 +1,32 @@
 package scaffold
 
 import (
+	"database/sql"
 	"errors"
 
 	"github.com/lib/pq"
 )
 
+func init() {
+	registerCellType(CellString, CellTypeDef{
+		Name:    "string",
+		New:     func() SQLCell { return NewSQLString() },
+		DBTypes: []string{"TEXT", "VARCHAR", "NVARCHAR", "BPCHAR", "CHAR", "CLOB", "TINYTEXT", "MEDIUMTEXT", "LONGTEXT", "ENUM"},
+		Columns: map[string]string{"": "TEXT"},
+		// mysql only indexes TEXT with a prefix length
+		KeyColumns: map[string]string{"mysql": "VARCHAR(255)"},
+		Null:       sql.NullString{},
+	})
+
+	registerCellType(CellStringArray, CellTypeDef{
+		Name:    "string array",
+		New:     func() SQLCell { return NewSQLStringArray() },
+		Array:   true,
+		DBTypes: []string{"TEXT[]", "VARCHAR[]", "NVARCHAR[]", "_TEXT", "_VARCHAR"},
+		Columns: map[string]string{"postgres": "TEXT[]", "mysql": "JSON"},
+	})
+}
+
 // SQLString representation of SQL
 type SQLString struct {
 	Valid bool
@@ -63,6 +84,11 @@ func (x *SQLStringArray) Target() interface{} {
 	return pq.Array(&x.Value)
 }
 
+// JSONTarget gets a scannable target for SQLStringArray stored as JSON
+func (x *SQLStringArray) JSONTarget() interface{} {
+	return &jsonArray{Valid: &x.Valid, Value: &x.Value}
+}
+
 // Scan interface->String
 func (x *SQLString) Scan(data interface{}) error {
 	switch data.(type) {
@@ -72,6 +98,9 @@ func (x *SQLString) Scan(data interface{}) error {
 			x.Valid = true
 			x.Value = v
 		}
+	case []byte:
+		x.Valid = true
+		x.Value = string(data.([]byte))
 	case nil:
 		x.Valid = false
 		x.Value = ""
@@ -0,0 +1,267 @@
+package scaffold
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func init() {
+	registerCellType(CellUUID, CellTypeDef{
+		Name:    "uuid",
+		New:     func() SQLCell { return NewSQLUUID() },
+		DBTypes: []string{"UUID", "UNIQUEIDENTIFIER"},
+		Columns: map[string]string{"postgres": "UUID", "sqlite": "TEXT", "mysql": "CHAR(36)"},
+		Null:    sql.NullString{},
+	})
+
+	registerCellType(CellUUIDArray, CellTypeDef{
+		Name:    "uuid array",
+		New:     func() SQLCell { return NewSQLUUIDArray() },
+		Array:   true,
+		DBTypes: []string{"UUID[]", "_UUID"},
+		Columns: map[string]string{"postgres": "UUID[]", "mysql": "JSON"},
+	})
+}
+
+// UUID is a 128 bit identifier, it binds and marshals as its canonical
+// text form
+type UUID [16]byte
+
+// UUIDGenerator makes a new UUID, set on a primary key cell it fills the
+// key on insert when the row leaves it unset
+type UUIDGenerator func() (UUID, error)
+
+// NewUUIDv4 makes a random UUID
+func NewUUIDv4() (UUID, error) {
+	var u UUID
+
+	_, err := rand.Read(u[:])
+	if err != nil {
+		return u, err
+	}
+
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+
+	return u, nil
+}
+
+// NewUUIDv7 makes a time ordered UUID, the first 48 bits are the unix time
+// in milliseconds, the rest is random
+func NewUUIDv7() (UUID, error) {
+	u, err := newTimeOrdered()
+	if err != nil {
+		return u, err
+	}
+
+	u[6] = (u[6] & 0x0f) | 0x70
+	u[8] = (u[8] & 0x3f) | 0x80
+
+	return u, nil
+}
+
+// NewULID makes a ULID, 48 bits of unix time in milliseconds followed by 80
+// random bits, held in a UUID so it fits uuid columns
+func NewULID() (UUID, error) {
+	return newTimeOrdered()
+}
+
+// newTimeOrdered fills a UUID with the time in milliseconds and random bits
+func newTimeOrdered() (UUID, error) {
+	var u UUID
+
+	_, err := rand.Read(u[6:])
+	if err != nil {
+		return u, err
+	}
+
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(time.Now().UnixNano()/int64(time.Millisecond)))
+	copy(u[:6], ts[2:])
+
+	return u, nil
+}
+
+// ParseUUID reads the canonical text form of a UUID, braces and missing
+// hyphens are accepted
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+
+	if len(s) == 38 && s[0] == '{' && s[37] == '}' {
+		s = s[1:37]
+	}
+
+	switch len(s) {
+	case 36:
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return u, errors.New("invalid UUID " + s)
+		}
+		s = s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	case 32:
+	default:
+		return u, errors.New("invalid UUID " + s)
+	}
+
+	_, err := hex.Decode(u[:], []byte(s))
+	if err != nil {
+		return u, errors.New("invalid UUID " + s)
+	}
+
+	return u, nil
+}
+
+// String is the canonical text form
+func (u UUID) String() string {
+	var b [36]byte
+
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+
+	return string(b[:])
+}
+
+// IsZero reports whether the UUID is all zeros
+func (u UUID) IsZero() bool {
+	return u == UUID{}
+}
+
+// Value binds the text form
+func (u UUID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+// Scan reads the text form or the 16 raw bytes
+func (u *UUID) Scan(data interface{}) error {
+	switch v := data.(type) {
+	case []byte:
+		if len(v) == 16 {
+			copy(u[:], v)
+			return nil
+		}
+
+		parsed, err := ParseUUID(string(v))
+		if err != nil {
+			return err
+		}
+		*u = parsed
+	case string:
+		parsed, err := ParseUUID(v)
+		if err != nil {
+			return err
+		}
+		*u = parsed
+	default:
+		return errors.New("Incompatible type")
+	}
+	return nil
+}
+
+// MarshalText writes the text form, used for JSON
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText reads the text form, used for JSON
+func (u *UUID) UnmarshalText(b []byte) error {
+	parsed, err := ParseUUID(string(b))
+	if err != nil {
+		return err
+	}
+
+	*u = parsed
+
+	return nil
+}
+
+// SQLUUID representation of SQL
+type SQLUUID struct {
+	Valid bool
+	Value UUID
+}
+
+// SQLUUIDArray representation of SQL
+type SQLUUIDArray struct {
+	Valid bool
+	Value []UUID
+}
+
+// NewSQLUUID makes a SQLUUID
+func NewSQLUUID() *SQLUUID {
+	x := new(SQLUUID)
+	x.Valid = false
+
+	return x
+}
+
+// NewSQLUUIDArray makes a SQLUUIDArray
+func NewSQLUUIDArray() *SQLUUIDArray {
+	x := new(SQLUUIDArray)
+	x.Valid = true
+	x.Value = make([]UUID, 0)
+
+	return x
+}
+
+// Raw UUID->Raw
+func (x *SQLUUID) Raw() (interface{}, error) {
+	if !x.Valid {
+		return x.Value, errors.New("Invalid value")
+	}
+
+	return x.Value, nil
+}
+
+// Raw UUIDArray->Raw
+func (x *SQLUUIDArray) Raw() (interface{}, error) {
+	if !x.Valid {
+		return x.Value, errors.New("Invalid value")
+	}
+
+	return x.Value, nil
+}
+
+// Target gets the scannable target for SQLUUID
+func (x *SQLUUID) Target() interface{} {
+	return x
+}
+
+// Target gets the scannable target for SQLUUIDArray
+func (x *SQLUUIDArray) Target() interface{} {
+	return pq.Array(&x.Value)
+}
+
+// JSONTarget gets a scannable target for SQLUUIDArray stored as JSON
+func (x *SQLUUIDArray) JSONTarget() interface{} {
+	return &jsonArray{Valid: &x.Valid, Value: &x.Value}
+}
+
+// Scan interface->UUID
+func (x *SQLUUID) Scan(data interface{}) error {
+	if data == nil {
+		x.Valid = false
+		x.Value = UUID{}
+		return nil
+	}
+
+	err := x.Value.Scan(data)
+	if err != nil {
+		return errors.New("Incompatible type")
+	}
+	x.Valid = true
+
+	return nil
+}
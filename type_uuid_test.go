@@ -0,0 +1,95 @@
+package scaffold
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseUUID(t *testing.T) {
+	want := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+
+	valid := []string{
+		want,
+		"{6ba7b810-9dad-11d1-80b4-00c04fd430c8}",
+		"6ba7b8109dad11d180b400c04fd430c8",
+		"6BA7B810-9DAD-11D1-80B4-00C04FD430C8",
+	}
+
+	for _, s := range valid {
+		u, err := ParseUUID(s)
+		if err != nil || u.String() != want {
+			t.Errorf("%s parsed as %s, err %v", s, u, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c",
+		"6ba7b810x9dad-11d1-80b4-00c04fd430c8",
+		"{6ba7b8109dad11d180b400c04fd430c8}",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430zz",
+	}
+
+	for _, s := range invalid {
+		_, err := ParseUUID(s)
+		if err == nil {
+			t.Errorf("%s parsed", s)
+		}
+	}
+}
+
+func TestUUIDScan(t *testing.T) {
+	want, _ := ParseUUID("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+
+	for _, data := range []interface{}{want[:], []byte(want.String()), want.String()} {
+		var u UUID
+
+		err := u.Scan(data)
+		if err != nil || u != want {
+			t.Errorf("%#v scanned as %s, err %v", data, u, err)
+		}
+	}
+
+	var u UUID
+
+	for _, data := range []interface{}{[]byte("short"), 42} {
+		if u.Scan(data) == nil {
+			t.Errorf("%#v scanned", data)
+		}
+	}
+}
+
+func TestUUIDVersions(t *testing.T) {
+	before := time.Now().UnixNano() / int64(time.Millisecond)
+
+	cases := []struct {
+		gen     UUIDGenerator
+		version byte
+	}{
+		{NewUUIDv4, 4},
+		{NewUUIDv7, 7},
+	}
+
+	for _, c := range cases {
+		u, err := c.gen()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if u[6]>>4 != c.version || u[8]>>6 != 2 {
+			t.Errorf("v%d: %s has version %d variant %b", c.version, u, u[6]>>4, u[8]>>6)
+		}
+	}
+
+	u, _ := NewUUIDv7()
+
+	var ms int64
+	for _, b := range u[:6] {
+		ms = ms<<8 | int64(b)
+	}
+
+	after := time.Now().UnixNano() / int64(time.Millisecond)
+	if ms < before || ms > after {
+		t.Errorf("v7 time %d outside %d..%d", ms, before, after)
+	}
+}
@@ -0,0 +1,179 @@
+package scaffold
+
+import (
+	"strings"
+)
+
+// ValidationError reports a query or table definition that cannot be
+// rendered safely, naming the offending field
+type ValidationError struct {
+	Field  string
+	Value  string
+	Reason string
+}
+
+// Error satisfies the error interface
+func (e *ValidationError) Error() string {
+	if e.Field == "" {
+		return "validation failed: " + e.Reason
+	}
+	return "invalid field \"" + e.Field + "\": " + e.Reason
+}
+
+var validOperators = map[string]bool{
+	"":    true,
+	"AND": true,
+	"OR":  true,
+}
+
+var validDirections = map[string]bool{
+	"":     true,
+	"ASC":  true,
+	"DESC": true,
+}
+
+// normalizeKeyword upper cases and trims a keyword for whitelist checks
+func normalizeKeyword(s string) string {
+	return strings.ToUpper(strings.TrimSpace(s))
+}
+
+// Validate checks a query before it is rendered, when a table is given
+// every field must be one of its cells
+func (q *Query) Validate(t *Table) error {
+	var cells map[string]bool
+
+	if t != nil {
+		cells = t.cellNames()
+	}
+
+	err := validateFilters(q.Filters, cells, t)
+	if err != nil {
+		return err
+	}
+
+	for _, o := range q.Orders {
+		err := validateField(o.Field, cells)
+		if err != nil {
+			return err
+		}
+
+		if !validDirections[normalizeKeyword(o.Direction)] {
+			return &ValidationError{Field: o.Field, Value: o.Direction, Reason: "unsupported order direction"}
+		}
+
+		if unorderedCell(t, o.Field) {
+			return &ValidationError{Field: o.Field, Reason: "cannot order by a cell whose values do not sort in " + t.client().dialect.Name()}
+		}
+	}
+
+	return nil
+}
+
+// validateFilters checks a list of filters and any groups within it, JSON
+// paths and containment need a JSON cell when the table is known
+func validateFilters(filters []Filter, cells map[string]bool, t *Table) error {
+	for _, f := range filters {
+		if !validOperators[normalizeKeyword(f.Operator)] {
+			return &ValidationError{Field: f.Field, Value: f.Operator, Reason: "unsupported operator"}
+		}
+
+		if f.Group != nil {
+			if len(f.Group) == 0 {
+				return &ValidationError{Field: f.Field, Reason: "empty filter group"}
+			}
+
+			err := validateFilters(f.Group, cells, t)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err := validateField(f.Field, cells)
+		if err != nil {
+			return err
+		}
+
+		if f.Path != "" {
+			for _, key := range splitPath(f.Path) {
+				if key == "" {
+					return &ValidationError{Field: f.Field, Value: f.Path, Reason: "empty key in JSON path"}
+				}
+			}
+		}
+
+		c, ok := f.Comparison.Normalize()
+		if !ok {
+			return &ValidationError{Field: f.Field, Value: string(f.Comparison), Reason: "unsupported comparison"}
+		}
+
+		if (f.Path != "" || c == JSONContains) && t != nil {
+			typ, _ := t.cellType(f.Field)
+			if typ != CellJSON {
+				return &ValidationError{Field: f.Field, Value: f.Path, Reason: "JSON paths and @> need a JSON cell"}
+			}
+		}
+
+		if f.Path == "" && c.ranged() && unorderedCell(t, f.Field) {
+			return &ValidationError{Field: f.Field, Value: string(c), Reason: "values do not sort in " + t.client().dialect.Name()}
+		}
+
+		err = checkOperands(c, f.Value)
+		if err != nil {
+			return &ValidationError{Field: f.Field, Value: string(c), Reason: err.Error()}
+		}
+	}
+
+	return nil
+}
+
+// unorderedCell reports whether a cell of the table does not compare in
+// value order in the dialect of its DB
+func unorderedCell(t *Table, field string) bool {
+	if t == nil || t.client().dialect == nil {
+		return false
+	}
+
+	typ, ok := t.cellType(field)
+	return ok && isUnordered(t.client().dialect.Name(), typ)
+}
+
+// validateField checks a field name exists when a cell list is known
+func validateField(field string, cells map[string]bool) error {
+	if field == "" {
+		return &ValidationError{Reason: "missing field name"}
+	}
+
+	if cells != nil && !cells[field] {
+		return &ValidationError{Field: field, Reason: "not a cell of the table"}
+	}
+
+	return nil
+}
+
+// Validate checks a table definition before it is used to build SQL
+func (t *Table) Validate() error {
+	if t.Name == "" {
+		return &ValidationError{Reason: "missing table name"}
+	}
+
+	seen := make(map[string]bool)
+
+	for _, c := range t.Cells {
+		if c.Name == "" {
+			return &ValidationError{Field: t.Name, Reason: "cell with missing name"}
+		}
+
+		if seen[c.Name] {
+			return &ValidationError{Field: c.Name, Reason: "duplicate cell"}
+		}
+
+		seen[c.Name] = true
+
+		if c.Generate != nil && (c.Type != CellUUID || !c.PrimaryKey) {
+			return &ValidationError{Field: c.Name, Reason: "only uuid primary keys can be generated"}
+		}
+	}
+
+	return nil
+}